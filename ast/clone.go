@@ -0,0 +1,168 @@
+package ast
+
+import "reflect"
+
+// Clone returns a deep copy of the document. Fragment spreads and variables
+// point to the copied definitions, while the schema definitions that
+// validation links the document to are shared with the original.
+func (d *QueryDocument) Clone() *QueryDocument {
+	return clone(d)
+}
+
+// Clone returns a deep copy of the document.
+func (d *SchemaDocument) Clone() *SchemaDocument {
+	return clone(d)
+}
+
+// Clone returns a deep copy of the schema. The root types, PossibleTypes and
+// Implements point to the copied types. Registered scalar parsers are copied
+// too, but share their functions with the original.
+func (s *Schema) Clone() *Schema {
+	return clone(s)
+}
+
+// cloneRefs lists the fields that point to nodes held elsewhere, in the same
+// tree or in another one, instead of holding them. They are pointed to the
+// copy of those nodes when it is made as part of the same clone, and left
+// alone otherwise.
+var cloneRefs = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Schema{}): {
+		"Query":         true,
+		"Mutation":      true,
+		"Subscription":  true,
+		"PossibleTypes": true,
+		"Implements":    true,
+	},
+	reflect.TypeOf(Field{}):              {"Definition": true, "ObjectDefinition": true},
+	reflect.TypeOf(FragmentSpread{}):     {"Definition": true, "ObjectDefinition": true},
+	reflect.TypeOf(InlineFragment{}):     {"ObjectDefinition": true},
+	reflect.TypeOf(FragmentDefinition{}): {"Definition": true},
+	reflect.TypeOf(VariableDefinition{}): {"Definition": true},
+	reflect.TypeOf(Directive{}):          {"Definition": true, "ParentDefinition": true},
+	reflect.TypeOf(Value{}): {
+		"ExpectedType":       true,
+		"Definition":         true,
+		"VariableDefinition": true,
+	},
+}
+
+// cloneShared lists the types that are never changed once made, so copies
+// share them with the original.
+var cloneShared = map[reflect.Type]bool{
+	reflect.TypeOf(&Position{}): true,
+	reflect.TypeOf(&Source{}):   true,
+}
+
+type cloner struct {
+	// clones maps the pointers copied so far to their copy.
+	clones map[any]any
+	// refs are the fields, in the copy, that are pointed once everything is
+	// copied, and the value they had in the original.
+	refs [][2]reflect.Value
+}
+
+func clone[T any](node *T) *T {
+	if node == nil {
+		return nil
+	}
+	c := &cloner{clones: map[any]any{}}
+	var copied *T
+	c.copy(reflect.ValueOf(&copied).Elem(), reflect.ValueOf(node))
+	for _, ref := range c.refs {
+		ref[0].Set(c.rewire(ref[1]))
+	}
+	return copied
+}
+
+// copy sets dst to a deep copy of src.
+func (c *cloner) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() || cloneShared[src.Type()] {
+			dst.Set(src)
+			return
+		}
+		if copied, ok := c.clones[src.Interface()]; ok {
+			dst.Set(reflect.ValueOf(copied))
+			return
+		}
+		copied := reflect.New(src.Type().Elem())
+		c.clones[src.Interface()] = copied.Interface()
+		dst.Set(copied)
+		c.copy(copied.Elem(), src.Elem())
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		c.copy(elem, src.Elem())
+		dst.Set(elem)
+	case reflect.Struct:
+		refs := cloneRefs[src.Type()]
+		for i := 0; i < src.NumField(); i++ {
+			switch field := src.Type().Field(i); {
+			case !field.IsExported():
+			case refs[field.Name]:
+				c.refs = append(c.refs, [2]reflect.Value{dst.Field(i), src.Field(i)})
+			default:
+				c.copy(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			c.copy(copied.Index(i), src.Index(i))
+		}
+		dst.Set(copied)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.MakeMapWithSize(src.Type(), src.Len())
+		for iter := src.MapRange(); iter.Next(); {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			c.copy(elem, iter.Value())
+			copied.SetMapIndex(iter.Key(), elem)
+		}
+		dst.Set(copied)
+	default:
+		dst.Set(src)
+	}
+}
+
+// rewire returns ref, with the pointers it holds replaced by their copy when
+// one was made.
+func (c *cloner) rewire(ref reflect.Value) reflect.Value {
+	switch ref.Kind() {
+	case reflect.Pointer:
+		if ref.IsNil() {
+			return ref
+		}
+		if copied, ok := c.clones[ref.Interface()]; ok {
+			return reflect.ValueOf(copied)
+		}
+		return ref
+	case reflect.Slice:
+		if ref.IsNil() {
+			return ref
+		}
+		rewired := reflect.MakeSlice(ref.Type(), ref.Len(), ref.Len())
+		for i := 0; i < ref.Len(); i++ {
+			rewired.Index(i).Set(c.rewire(ref.Index(i)))
+		}
+		return rewired
+	case reflect.Map:
+		if ref.IsNil() {
+			return ref
+		}
+		rewired := reflect.MakeMapWithSize(ref.Type(), ref.Len())
+		for iter := ref.MapRange(); iter.Next(); {
+			rewired.SetMapIndex(iter.Key(), c.rewire(iter.Value()))
+		}
+		return rewired
+	}
+	return ref
+}
@@ -0,0 +1,76 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestClone(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&Source{Input: `
+		type Query { pet(name: String): Pet }
+		interface Pet { name: String }
+		type Dog implements Pet { name: String }
+		scalar Date
+	`})
+	schema.RegisterScalar("Date", nil, nil)
+
+	t.Run("query document", func(t *testing.T) {
+		doc, errs := gqlparser.LoadQueryWithRules(schema, `
+			query ($name: String) { pet(name: $name) { ...Named } }
+			fragment Named on Pet { name }
+		`, nil)
+		require.Empty(t, errs)
+
+		copied := doc.Clone()
+		require.Equal(t, doc, copied)
+
+		pet := copied.Operations[0].SelectionSet[0].(*Field)
+		require.NotSame(t, doc.Operations[0].SelectionSet[0], pet)
+		require.Same(t, schema.Query.Fields.ForName("pet"), pet.Definition)
+		require.Same(t, schema.Query, pet.ObjectDefinition)
+		require.Same(t, copied.Operations[0].VariableDefinitions[0],
+			pet.Arguments[0].Value.VariableDefinition)
+		require.Same(t, pet.Definition.Arguments[0].Type, pet.Arguments[0].Value.ExpectedType)
+		require.Same(t, copied.Fragments[0], pet.SelectionSet[0].(*FragmentSpread).Definition)
+
+		copied.Fragments[0].SelectionSet[0].(*Field).Alias = "alias"
+		require.Equal(t, "name", doc.Fragments[0].SelectionSet[0].(*Field).Alias)
+		require.Nil(t, (*QueryDocument)(nil).Clone())
+	})
+
+	t.Run("schema document", func(t *testing.T) {
+		doc, err := parser.ParseSchema(&Source{Input: `
+			type Query @key(fields: "id") { id: ID! list(first: Int = 10): [String] }
+		`})
+		require.NoError(t, err)
+
+		copied := doc.Clone()
+		require.Equal(t, doc, copied)
+		require.NotSame(t, doc.Definitions[0].Fields[1].Arguments[0].DefaultValue,
+			copied.Definitions[0].Fields[1].Arguments[0].DefaultValue)
+		require.Same(t, doc.Definitions[0].Position, copied.Definitions[0].Position)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		copied := schema.Clone()
+		require.Equal(t, schema.Types, copied.Types)
+		require.Equal(t, schema.Directives, copied.Directives)
+
+		require.NotSame(t, schema.Query, copied.Query)
+		require.Same(t, copied.Types["Query"], copied.Query)
+		require.Nil(t, copied.Mutation)
+		require.Same(t, copied.Types["Dog"], copied.PossibleTypes["Pet"][0])
+		require.Same(t, copied.Types["Pet"], copied.Implements["Dog"][0])
+		require.NotSame(t, schema.Directives["skip"], copied.Directives["skip"])
+		require.NotNil(t, copied.Scalars["Date"])
+		require.NotSame(t, schema.Scalars["Date"], copied.Scalars["Date"])
+
+		copied.Types["Dog"].Fields[0].Name = "renamed"
+		require.Equal(t, "name", schema.Types["Dog"].Fields[0].Name)
+	})
+}
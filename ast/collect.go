@@ -0,0 +1,117 @@
+package ast
+
+// CollectedField is every field selected under the same response key.
+type CollectedField struct {
+	ResponseKey string
+	Fields      []*Field
+}
+
+// SelectionSet merges the selection sets of the fields, which is what the
+// value of the response key is completed with.
+func (f *CollectedField) SelectionSet() SelectionSet {
+	if len(f.Fields) == 1 {
+		return f.Fields[0].SelectionSet
+	}
+	var selectionSet SelectionSet
+	for _, field := range f.Fields {
+		selectionSet = append(selectionSet, field.SelectionSet...)
+	}
+	return selectionSet
+}
+
+// CollectFields is CollectFields from the spec. It flattens the fragments of
+// selectionSet that apply to objectType, drops what @skip and @include rule
+// out given the variables, and groups the fields by response key in the order
+// they are first selected. The variables should already have been coerced,
+// as by validator.VariableValues, so that their defaults are filled in.
+//
+// Fragment spreads are followed through their Definition, so selectionSet
+// must come from a validated document.
+func CollectFields(
+	schema *Schema,
+	selectionSet SelectionSet,
+	objectType *Definition,
+	variables map[string]any,
+) []*CollectedField {
+	var result []*CollectedField
+	index := map[string]*CollectedField{}
+	visited := map[string]bool{}
+
+	var collect func(selectionSet SelectionSet)
+	collect = func(selectionSet SelectionSet) {
+		for _, selection := range selectionSet {
+			switch sel := selection.(type) {
+			case *Field:
+				if !shouldInclude(sel.Directives, variables) {
+					continue
+				}
+				key := sel.Alias
+				if key == "" {
+					key = sel.Name
+				}
+				f := index[key]
+				if f == nil {
+					f = &CollectedField{ResponseKey: key}
+					index[key] = f
+					result = append(result, f)
+				}
+				f.Fields = append(f.Fields, sel)
+			case *InlineFragment:
+				if !shouldInclude(sel.Directives, variables) ||
+					!fragmentApplies(schema, sel.TypeCondition, objectType) {
+					continue
+				}
+				collect(sel.SelectionSet)
+			case *FragmentSpread:
+				if visited[sel.Name] || !shouldInclude(sel.Directives, variables) {
+					continue
+				}
+				visited[sel.Name] = true
+				fragment := sel.Definition
+				if fragment == nil || !fragmentApplies(schema, fragment.TypeCondition, objectType) {
+					continue
+				}
+				collect(fragment.SelectionSet)
+			}
+		}
+	}
+	collect(selectionSet)
+
+	return result
+}
+
+func shouldInclude(directives DirectiveList, variables map[string]any) bool {
+	if skip := directives.ForName("skip"); skip != nil && ifArgument(skip, variables) {
+		return false
+	}
+	if include := directives.ForName("include"); include != nil &&
+		!ifArgument(include, variables) {
+		return false
+	}
+	return true
+}
+
+func ifArgument(directive *Directive, variables map[string]any) bool {
+	if arg := directive.Arguments.ForName("if"); arg != nil && arg.Value != nil {
+		value, _ := arg.Value.Value(variables)
+		b, _ := value.(bool)
+		return b
+	}
+	return false
+}
+
+func fragmentApplies(schema *Schema, typeCondition string, objectType *Definition) bool {
+	if typeCondition == "" || typeCondition == objectType.Name {
+		return true
+	}
+	def := schema.Types[typeCondition]
+	if def == nil {
+		return false
+	}
+	for _, possible := range schema.GetPossibleTypes(def) {
+		if possible.Name == objectType.Name {
+			return true
+		}
+	}
+	return false
+}
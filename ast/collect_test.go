@@ -0,0 +1,74 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	. "github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCollectFields(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&Source{Input: `
+		type Query { pet: Pet }
+		interface Pet { name: String }
+		type Dog implements Pet { name: String barks: Boolean owner: Human }
+		type Cat implements Pet { name: String meows: Boolean }
+		type Human { name: String age: Int }
+	`})
+	doc, errs := gqlparser.LoadQueryWithRules(schema, `
+		query ($skip: Boolean!, $include: Boolean = true) {
+			pet {
+				name
+				... on Dog { barks owner { name } }
+				... on Cat { meows }
+				...DogOwner
+				...DogOwner
+				alias: name @skip(if: $skip)
+				... @include(if: $include) { name }
+				... on Pet @skip(if: true) { skipped: name }
+				...CatName @include(if: false)
+			}
+		}
+		fragment DogOwner on Dog { owner { age } barks }
+		fragment CatName on Cat { name }
+	`, nil)
+	require.Empty(t, errs)
+
+	pet := doc.Operations[0].SelectionSet[0].(*Field)
+	keys := func(fields []*CollectedField) []string {
+		var keys []string
+		for _, f := range fields {
+			keys = append(keys, f.ResponseKey)
+		}
+		return keys
+	}
+
+	t.Run("applies type conditions", func(t *testing.T) {
+		vars := map[string]any{"skip": false, "include": true}
+		dog := CollectFields(schema, pet.SelectionSet, schema.Types["Dog"], vars)
+		require.Equal(t, []string{"name", "barks", "owner", "alias"}, keys(dog))
+		require.Len(t, dog[0].Fields, 2)
+		require.Len(t, dog[1].Fields, 2)
+
+		cat := CollectFields(schema, pet.SelectionSet, schema.Types["Cat"], vars)
+		require.Equal(t, []string{"name", "meows", "alias"}, keys(cat))
+	})
+
+	t.Run("evaluates skip and include", func(t *testing.T) {
+		vars := map[string]any{"skip": true, "include": false}
+		dog := CollectFields(schema, pet.SelectionSet, schema.Types["Dog"], vars)
+		require.Equal(t, []string{"name", "barks", "owner"}, keys(dog))
+		require.Len(t, dog[0].Fields, 1)
+	})
+
+	t.Run("merges selection sets", func(t *testing.T) {
+		dog := CollectFields(schema, pet.SelectionSet, schema.Types["Dog"], nil)
+		owner := dog[2]
+		require.Len(t, owner.SelectionSet(), 2)
+
+		human := CollectFields(schema, owner.SelectionSet(), schema.Types["Human"], nil)
+		require.Equal(t, []string{"name", "age"}, keys(human))
+	})
+}
@@ -37,6 +37,10 @@ type Schema struct {
 	PossibleTypes map[string][]*Definition
 	Implements    map[string][]*Definition
 
+	// Scalars validates the values of custom scalars. It is not loaded from
+	// SDL, but registered with RegisterScalar.
+	Scalars ScalarRegistry `dump:"-" json:"-"`
+
 	Description string
 
 	Comment *CommentGroup
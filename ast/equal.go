@@ -0,0 +1,237 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// EqualOption configures Equal.
+type EqualOption func(*equaler)
+
+// EqualIgnorePositions leaves positions out of the comparison, so that the
+// same document parsed from differently laid out sources is equal.
+func EqualIgnorePositions() EqualOption {
+	return func(e *equaler) {
+		e.ignorePositions = true
+	}
+}
+
+// EqualIgnoreComments leaves comments out of the comparison.
+func EqualIgnoreComments() EqualOption {
+	return func(e *equaler) {
+		e.ignoreComments = true
+	}
+}
+
+// EqualIgnoreDescriptions leaves the descriptions of schema elements out of the
+// comparison.
+func EqualIgnoreDescriptions() EqualOption {
+	return func(e *equaler) {
+		e.ignoreDescriptions = true
+	}
+}
+
+// EqualIgnoreOrder compares selection sets and argument lists without regard
+// to the order of their items.
+func EqualIgnoreOrder() EqualOption {
+	return func(e *equaler) {
+		e.ignoreOrder = true
+	}
+}
+
+// Equal compares two nodes, e.g. documents, definitions, selections, values or
+// types, and everything they hold. When they differ, it also describes the
+// first difference found, e.g.
+//
+//	Operations[0].SelectionSet[1].Name: "name" != "title"
+//
+// What validation links nodes to, such as Field.Definition, is not compared.
+func Equal(a, b any, options ...EqualOption) (bool, string) {
+	e := &equaler{}
+	for _, option := range options {
+		option(e)
+	}
+
+	diff := e.diff(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return diff == "", diff
+}
+
+// equalSkipped lists the fields that are filled in by validation, which mostly
+// point to the schema, and the scalar parsers of a Schema.
+var equalSkipped = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Field{}):              {"Definition": true, "ObjectDefinition": true},
+	reflect.TypeOf(FragmentSpread{}):     {"Definition": true, "ObjectDefinition": true},
+	reflect.TypeOf(InlineFragment{}):     {"ObjectDefinition": true},
+	reflect.TypeOf(FragmentDefinition{}): {"Definition": true},
+	reflect.TypeOf(VariableDefinition{}): {"Definition": true, "Used": true},
+	reflect.TypeOf(Directive{}): {
+		"Definition":       true,
+		"ParentDefinition": true,
+		"Location":         true,
+	},
+	reflect.TypeOf(Value{}): {
+		"ExpectedType":           true,
+		"ExpectedTypeHasDefault": true,
+		"Definition":             true,
+		"VariableDefinition":     true,
+	},
+	reflect.TypeOf(Schema{}): {"Scalars": true},
+}
+
+var (
+	positionType     = reflect.TypeOf(&Position{})
+	positionListType = reflect.TypeOf([]*Position{})
+	commentType      = reflect.TypeOf(&CommentGroup{})
+	unorderedTypes   = map[reflect.Type]bool{
+		reflect.TypeOf(SelectionSet{}): true,
+		reflect.TypeOf(ArgumentList{}): true,
+	}
+)
+
+type equaler struct {
+	ignorePositions    bool
+	ignoreComments     bool
+	ignoreDescriptions bool
+	ignoreOrder        bool
+}
+
+// diff describes the first difference between a and b, found at path, or
+// returns "" when they are equal.
+func (e *equaler) diff(path Path, a, b reflect.Value) string {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			return differ(path, "%s != %s", describe(a), describe(b))
+		}
+		return ""
+	}
+	if a.Type() != b.Type() {
+		return differ(path, "%s != %s", a.Type(), b.Type())
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				return differ(path, "%s != %s", describe(a), describe(b))
+			}
+			return ""
+		}
+		return e.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		skipped := equalSkipped[a.Type()]
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() || skipped[field.Name] || e.ignored(field) {
+				continue
+			}
+			fieldPath := append(slices.Clip(path), PathName(field.Name))
+			if diff := e.diff(fieldPath, a.Field(i), b.Field(i)); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return differ(path, "%d items != %d items", a.Len(), b.Len())
+		}
+		if e.ignoreOrder && unorderedTypes[a.Type()] {
+			return e.diffUnordered(path, a, b)
+		}
+		for i := 0; i < a.Len(); i++ {
+			itemPath := append(slices.Clip(path), PathIndex(i))
+			if diff := e.diff(itemPath, a.Index(i), b.Index(i)); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	case reflect.Map:
+		for _, key := range mapKeys(a, b) {
+			keyPath := append(slices.Clip(path), PathName(key))
+			k := reflect.ValueOf(key).Convert(a.Type().Key())
+			if diff := e.diff(keyPath, a.MapIndex(k), b.MapIndex(k)); diff != "" {
+				return diff
+			}
+		}
+		return ""
+	case reflect.Func:
+		if a.IsNil() != b.IsNil() {
+			return differ(path, "%s != %s", describe(a), describe(b))
+		}
+		return ""
+	}
+
+	if !a.Equal(b) {
+		return differ(path, "%s != %s", describe(a), describe(b))
+	}
+	return ""
+}
+
+// diffUnordered pairs the items of a with equal items of b, in any order.
+func (e *equaler) diffUnordered(path Path, a, b reflect.Value) string {
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := 0; j < b.Len() && !found; j++ {
+			if !matched[j] && e.diff(nil, a.Index(i), b.Index(j)) == "" {
+				matched[j] = true
+				found = true
+			}
+		}
+		if !found {
+			itemPath := append(slices.Clip(path), PathIndex(i))
+			return differ(itemPath, "no equal item in the other list")
+		}
+	}
+	return ""
+}
+
+// ignored reports whether the options leave field out of the comparison.
+func (e *equaler) ignored(field reflect.StructField) bool {
+	switch {
+	case e.ignorePositions && (field.Type == positionType || field.Type == positionListType):
+		return true
+	case e.ignoreComments && field.Type == commentType:
+		return true
+	case e.ignoreDescriptions && field.Name == "Description":
+		return true
+	}
+	return false
+}
+
+func differ(path Path, format string, args ...any) string {
+	if len(path) == 0 {
+		return fmt.Sprintf(format, args...)
+	}
+	return path.String() + ": " + fmt.Sprintf(format, args...)
+}
+
+// describe formats v for a difference.
+func describe(v reflect.Value) string {
+	switch {
+	case !v.IsValid():
+		return "nil"
+	case v.Kind() == reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case v.Kind() == reflect.Pointer, v.Kind() == reflect.Interface, v.Kind() == reflect.Func:
+		if v.IsNil() {
+			return "nil"
+		}
+		return v.Type().String()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// mapKeys are the keys of a and b, sorted.
+func mapKeys(a, b reflect.Value) []string {
+	var keys []string
+	for _, m := range []reflect.Value{a, b} {
+		for iter := m.MapRange(); iter.Next(); {
+			if key := iter.Key().String(); !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
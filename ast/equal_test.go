@@ -0,0 +1,108 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestEqual(t *testing.T) {
+	parseQuery := func(t *testing.T, query string) *QueryDocument {
+		t.Helper()
+		doc, err := parser.ParseQuery(&Source{Input: query})
+		require.NoError(t, err)
+		return doc
+	}
+	parseSchema := func(t *testing.T, sdl string) *SchemaDocument {
+		t.Helper()
+		doc, err := parser.ParseSchema(&Source{Input: sdl})
+		require.NoError(t, err)
+		return doc
+	}
+
+	t.Run("positions", func(t *testing.T) {
+		a := parseQuery(t, `{ a(x: 1) { b } }`)
+		b := parseQuery(t, "{\n  a(x: 1) {\n    b\n  }\n}")
+
+		equal, diff := Equal(a, b)
+		require.False(t, equal)
+		require.Equal(t,
+			"Operations[0].SelectionSet[0].Arguments[0].Value.Position.Start: 7 != 9", diff)
+
+		equal, diff = Equal(a, b, EqualIgnorePositions())
+		require.True(t, equal, diff)
+		require.Empty(t, diff)
+	})
+
+	t.Run("first difference", func(t *testing.T) {
+		a := parseQuery(t, `{ a { b c(x: [1, 2]) } }`)
+		b := parseQuery(t, `{ a { b c(x: [1, 3]) } }`)
+		_, diff := Equal(a, b, EqualIgnorePositions())
+		require.Equal(t, `Operations[0].SelectionSet[0].SelectionSet[1].Arguments[0].`+
+			`Value.Children[1].Value.Raw: "2" != "3"`, diff)
+
+		_, diff = Equal(a.Operations[0], parseQuery(t, `{ a { b } }`).Operations[0],
+			EqualIgnorePositions())
+		require.Equal(t, "SelectionSet[0].SelectionSet: 2 items != 1 items", diff)
+
+		_, diff = Equal(a.Operations[0].SelectionSet[0], &FragmentSpread{Name: "a"})
+		require.Equal(t, "*ast.Field != *ast.FragmentSpread", diff)
+
+		_, diff = Equal(a, (*QueryDocument)(nil))
+		require.Equal(t, "*ast.QueryDocument != nil", diff)
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		a := parseQuery(t, "# fetch a\nquery { a }")
+		b := parseQuery(t, "# fetch b\nquery { a }")
+		equal, diff := Equal(a, b, EqualIgnorePositions())
+		require.False(t, equal)
+		require.Equal(t, `Operations[0].Comment.List[0].Value: "# fetch a" != "# fetch b"`, diff)
+		equal, _ = Equal(a, b, EqualIgnorePositions(), EqualIgnoreComments())
+		require.True(t, equal)
+	})
+
+	t.Run("descriptions", func(t *testing.T) {
+		a := parseSchema(t, `"Root" type Query { "The answer" answer: Int }`)
+		b := parseSchema(t, `type Query { answer: Int }`)
+		_, diff := Equal(a, b, EqualIgnorePositions())
+		require.Equal(t, `Definitions[0].Description: "Root" != ""`, diff)
+		equal, _ := Equal(a, b, EqualIgnorePositions(), EqualIgnoreDescriptions())
+		require.True(t, equal)
+	})
+
+	t.Run("order", func(t *testing.T) {
+		a := parseQuery(t, `{ a(x: 1, y: 2) { b c } d }`)
+		b := parseQuery(t, `{ d a(y: 2, x: 1) { c b } }`)
+		equal, _ := Equal(a, b, EqualIgnorePositions())
+		require.False(t, equal)
+		equal, diff := Equal(a, b, EqualIgnorePositions(), EqualIgnoreOrder())
+		require.True(t, equal, diff)
+
+		c := parseQuery(t, `{ d a(y: 2, x: 1) { c e } }`)
+		_, diff = Equal(a, c, EqualIgnorePositions(), EqualIgnoreOrder())
+		require.Equal(t, "Operations[0].SelectionSet[0]: no equal item in the other list", diff)
+	})
+
+	t.Run("validation is not compared", func(t *testing.T) {
+		schema := gqlparser.MustLoadSchema(&Source{Input: `type Query { a: Int }`})
+		validated, errs := gqlparser.LoadQueryWithRules(schema, `{ a }`, nil)
+		require.Empty(t, errs)
+		equal, diff := Equal(parseQuery(t, `{ a }`), validated, EqualIgnorePositions())
+		require.True(t, equal, diff)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		a := gqlparser.MustLoadSchema(&Source{Input: `type Query { a: Int }`})
+		b := gqlparser.MustLoadSchema(&Source{Input: `type Query { a: String }`})
+		_, diff := Equal(a, b, EqualIgnorePositions())
+		require.Equal(t, `Query.Fields[0].Type.NamedType: "Int" != "String"`, diff)
+
+		equal, diff := Equal(a, a.Clone())
+		require.True(t, equal, diff)
+	})
+}
@@ -0,0 +1,586 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// UnmarshalQueryDocumentGraphQLJS decodes a document from JSON in the shape of
+// the graphql-js AST, as written by QueryDocument.MarshalGraphQLJS or by
+// JSON.stringify on a graphql-js DocumentNode.
+//
+// Positions are read from the loc of each node, and point to src. When a loc
+// has no line and column, as graphql-js writes it, they are worked out from
+// src. Nodes without a loc have no position.
+func UnmarshalQueryDocumentGraphQLJS(data []byte, src *Source) (*QueryDocument, error) {
+	d, root, err := newJSDecoder(data, src)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &QueryDocument{Position: d.position(root.Loc)}
+	for _, def := range root.Definitions {
+		if !d.expect(def, "OperationDefinition", "FragmentDefinition") {
+			continue
+		}
+		if def.Kind == "OperationDefinition" {
+			doc.Operations = append(doc.Operations, d.operationDefinition(def))
+		} else {
+			doc.Fragments = append(doc.Fragments, d.fragmentDefinition(def))
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return doc, nil
+}
+
+// UnmarshalSchemaDocumentGraphQLJS decodes a document from JSON in the shape of
+// the graphql-js AST, like UnmarshalQueryDocumentGraphQLJS does. The
+// definitions are marked BuiltIn when src is.
+func UnmarshalSchemaDocumentGraphQLJS(data []byte, src *Source) (*SchemaDocument, error) {
+	d, root, err := newJSDecoder(data, src)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &SchemaDocument{Position: d.position(root.Loc)}
+	for _, def := range root.Definitions {
+		if def == nil {
+			d.error("missing type system definition")
+			continue
+		}
+		switch {
+		case def.Kind == "SchemaDefinition":
+			doc.Schema = append(doc.Schema, d.schemaDefinition(def))
+		case def.Kind == "SchemaExtension":
+			doc.SchemaExtension = append(doc.SchemaExtension, d.schemaDefinition(def))
+		case def.Kind == "DirectiveDefinition":
+			doc.Directives = append(doc.Directives, d.directiveDefinition(def))
+		case strings.HasSuffix(def.Kind, "TypeDefinition"):
+			doc.Definitions = append(doc.Definitions,
+				d.definition(def, strings.TrimSuffix(def.Kind, "TypeDefinition")))
+		case strings.HasSuffix(def.Kind, "TypeExtension"):
+			doc.Extensions = append(doc.Extensions,
+				d.definition(def, strings.TrimSuffix(def.Kind, "TypeExtension")))
+		default:
+			d.unexpected(def, "a type system definition")
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return doc, nil
+}
+
+// jsInput holds a graphql-js AST node of any kind. Keys that mean different
+// things in different kinds, like value, are decoded once the kind is known.
+type jsInput struct {
+	Kind                string          `json:"kind"`
+	Loc                 *jsLocation     `json:"loc"`
+	Name                *jsInput        `json:"name"`
+	Alias               *jsInput        `json:"alias"`
+	Description         *jsInput        `json:"description"`
+	Value               json.RawMessage `json:"value"`
+	Block               bool            `json:"block"`
+	Operation           Operation       `json:"operation"`
+	Variable            *jsInput        `json:"variable"`
+	VariableDefinitions []*jsInput      `json:"variableDefinitions"`
+	Type                *jsInput        `json:"type"`
+	TypeCondition       *jsInput        `json:"typeCondition"`
+	DefaultValue        *jsInput        `json:"defaultValue"`
+	Arguments           []*jsInput      `json:"arguments"`
+	Directives          []*jsInput      `json:"directives"`
+	SelectionSet        *jsInput        `json:"selectionSet"`
+	Selections          []*jsInput      `json:"selections"`
+	Values              []*jsInput      `json:"values"`
+	Fields              []*jsInput      `json:"fields"`
+	Definitions         []*jsInput      `json:"definitions"`
+	OperationTypes      []*jsInput      `json:"operationTypes"`
+	Interfaces          []*jsInput      `json:"interfaces"`
+	Types               []*jsInput      `json:"types"`
+	Repeatable          bool            `json:"repeatable"`
+	Locations           []*jsInput      `json:"locations"`
+}
+
+// jsDecoder turns jsInput into nodes. Like the parser, it keeps the first
+// error it runs into and makes do with zero values after it.
+type jsDecoder struct {
+	src *Source
+	err error
+	// lineStarts holds the rune offset each line of src starts at, once a
+	// position has needed it.
+	lineStarts []int
+}
+
+func newJSDecoder(data []byte, src *Source) (*jsDecoder, *jsInput, error) {
+	var root jsInput
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	if root.Kind != "Document" {
+		return nil, nil, fmt.Errorf("unexpected kind %q, expected Document", root.Kind)
+	}
+	return &jsDecoder{src: src}, &root, nil
+}
+
+func (d *jsDecoder) error(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (d *jsDecoder) unexpected(node *jsInput, expected string) {
+	d.error("unexpected kind %q, expected %s", node.Kind, expected)
+}
+
+// expect reports whether node is of one of kinds, and records an error
+// otherwise.
+func (d *jsDecoder) expect(node *jsInput, kinds ...string) bool {
+	expected := kinds[len(kinds)-1]
+	if len(kinds) > 1 {
+		expected = strings.Join(kinds[:len(kinds)-1], ", ") + " or " + expected
+	}
+	if node == nil {
+		d.error("missing %s", expected)
+		return false
+	}
+	if !slices.Contains(kinds, node.Kind) {
+		d.unexpected(node, expected)
+		return false
+	}
+	return true
+}
+
+// operation decodes the operation of node, which is one of query, mutation
+// or subscription.
+func (d *jsDecoder) operation(node *jsInput) Operation {
+	switch node.Operation {
+	case Query, Mutation, Subscription:
+	default:
+		d.error(
+			"unexpected operation %q of %s, expected query, mutation or subscription",
+			node.Operation,
+			node.Kind,
+		)
+	}
+	return node.Operation
+}
+
+func (d *jsDecoder) position(loc *jsLocation) *Position {
+	if loc == nil {
+		return nil
+	}
+	if loc.Start < 0 {
+		d.error("invalid loc start %d", loc.Start)
+		return nil
+	}
+	pos := &Position{
+		Start:  loc.Start,
+		End:    loc.End,
+		Line:   loc.Line,
+		Column: loc.Column,
+		Src:    d.src,
+	}
+	if pos.Line == 0 && d.src != nil {
+		if d.lineStarts == nil {
+			d.lineStarts = lineStarts(d.src.Input)
+		}
+		line := sort.Search(len(d.lineStarts), func(i int) bool {
+			return d.lineStarts[i] > pos.Start
+		})
+		pos.Line = line
+		pos.Column = pos.Start - d.lineStarts[line-1] + 1
+	}
+	return pos
+}
+
+// lineStarts returns the rune offset of the start of each line of input,
+// which are broken by "\n", "\r" or "\r\n" as the lexer breaks them.
+func lineStarts(input string) []int {
+	starts := []int{0}
+	offset := 0
+	cr := false
+	for _, r := range input {
+		offset++
+		switch {
+		case r == '\n' && cr:
+			starts[len(starts)-1] = offset
+		case r == '\n' || r == '\r':
+			starts = append(starts, offset)
+		}
+		cr = r == '\r'
+	}
+	return starts
+}
+
+// string decodes the value of node, which holds a string.
+func (d *jsDecoder) string(node *jsInput) string {
+	var value string
+	if err := json.Unmarshal(node.Value, &value); err != nil {
+		d.error("value of %s: %w", node.Kind, err)
+	}
+	return value
+}
+
+func (d *jsDecoder) name(node *jsInput) string {
+	if !d.expect(node, "Name") {
+		return ""
+	}
+	return d.string(node)
+}
+
+func (d *jsDecoder) description(node *jsInput) string {
+	if node == nil || !d.expect(node, "StringValue") {
+		return ""
+	}
+	return d.string(node)
+}
+
+func (d *jsDecoder) namedType(node *jsInput) string {
+	if !d.expect(node, "NamedType") {
+		return ""
+	}
+	return d.name(node.Name)
+}
+
+func (d *jsDecoder) operationDefinition(node *jsInput) *OperationDefinition {
+	def := &OperationDefinition{
+		Operation:           d.operation(node),
+		VariableDefinitions: d.variableDefinitions(node.VariableDefinitions),
+		Directives:          d.directives(node.Directives),
+		SelectionSet:        d.selectionSet(node.SelectionSet),
+		Position:            d.position(node.Loc),
+	}
+	if node.Name != nil {
+		def.Name = d.name(node.Name)
+	}
+	return def
+}
+
+func (d *jsDecoder) fragmentDefinition(node *jsInput) *FragmentDefinition {
+	return &FragmentDefinition{
+		Name:               d.name(node.Name),
+		VariableDefinition: d.variableDefinitions(node.VariableDefinitions),
+		TypeCondition:      d.namedType(node.TypeCondition),
+		Directives:         d.directives(node.Directives),
+		SelectionSet:       d.selectionSet(node.SelectionSet),
+		Position:           d.position(node.Loc),
+	}
+}
+
+func (d *jsDecoder) variableDefinitions(nodes []*jsInput) VariableDefinitionList {
+	var defs VariableDefinitionList
+	for _, node := range nodes {
+		if !d.expect(node, "VariableDefinition") || !d.expect(node.Variable, "Variable") {
+			continue
+		}
+		def := &VariableDefinition{
+			Variable:   d.name(node.Variable.Name),
+			Type:       d.typ(node.Type),
+			Directives: d.directives(node.Directives),
+			Position:   d.position(node.Loc),
+		}
+		if node.DefaultValue != nil {
+			def.DefaultValue = d.value(node.DefaultValue)
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+func (d *jsDecoder) selectionSet(node *jsInput) SelectionSet {
+	if node == nil || !d.expect(node, "SelectionSet") {
+		return nil
+	}
+	var selections SelectionSet
+	for _, selection := range node.Selections {
+		if !d.expect(selection, "Field", "FragmentSpread", "InlineFragment") {
+			continue
+		}
+		switch selection.Kind {
+		case "Field":
+			field := &Field{
+				Name:         d.name(selection.Name),
+				Arguments:    d.arguments(selection.Arguments),
+				Directives:   d.directives(selection.Directives),
+				SelectionSet: d.selectionSet(selection.SelectionSet),
+				Position:     d.position(selection.Loc),
+			}
+			field.Alias = field.Name
+			if selection.Alias != nil {
+				field.Alias = d.name(selection.Alias)
+			}
+			selections = append(selections, field)
+		case "FragmentSpread":
+			selections = append(selections, &FragmentSpread{
+				Name:       d.name(selection.Name),
+				Directives: d.directives(selection.Directives),
+				Position:   d.position(selection.Loc),
+			})
+		case "InlineFragment":
+			fragment := &InlineFragment{
+				Directives:   d.directives(selection.Directives),
+				SelectionSet: d.selectionSet(selection.SelectionSet),
+				Position:     d.position(selection.Loc),
+			}
+			if selection.TypeCondition != nil {
+				fragment.TypeCondition = d.namedType(selection.TypeCondition)
+			}
+			selections = append(selections, fragment)
+		}
+	}
+	return selections
+}
+
+func (d *jsDecoder) arguments(nodes []*jsInput) ArgumentList {
+	var arguments ArgumentList
+	for _, node := range nodes {
+		if !d.expect(node, "Argument") {
+			continue
+		}
+		arguments = append(arguments, &Argument{
+			Name:     d.name(node.Name),
+			Value:    d.value(d.child(node)),
+			Position: d.position(node.Loc),
+		})
+	}
+	return arguments
+}
+
+func (d *jsDecoder) directives(nodes []*jsInput) DirectiveList {
+	var directives DirectiveList
+	for _, node := range nodes {
+		if !d.expect(node, "Directive") {
+			continue
+		}
+		directives = append(directives, &Directive{
+			Name:      d.name(node.Name),
+			Arguments: d.arguments(node.Arguments),
+			Position:  d.position(node.Loc),
+		})
+	}
+	return directives
+}
+
+// child decodes the value of node, which holds a value node.
+func (d *jsDecoder) child(node *jsInput) *jsInput {
+	var child *jsInput
+	if err := json.Unmarshal(node.Value, &child); err != nil {
+		d.error("value of %s: %w", node.Kind, err)
+	}
+	if child == nil {
+		d.error("missing value of %s", node.Kind)
+	}
+	return child
+}
+
+func (d *jsDecoder) value(node *jsInput) *Value {
+	if node == nil {
+		d.error("missing value")
+		return &Value{Kind: NullValue, Raw: "null"}
+	}
+	value := &Value{Position: d.position(node.Loc)}
+	switch node.Kind {
+	case "Variable":
+		value.Kind = Variable
+		value.Raw = d.name(node.Name)
+	case "IntValue":
+		value.Kind = IntValue
+		value.Raw = d.string(node)
+	case "FloatValue":
+		value.Kind = FloatValue
+		value.Raw = d.string(node)
+	case "StringValue":
+		value.Kind = StringValue
+		if node.Block {
+			value.Kind = BlockValue
+		}
+		value.Raw = d.string(node)
+	case "BooleanValue":
+		var b bool
+		if err := json.Unmarshal(node.Value, &b); err != nil {
+			d.error("value of %s: %w", node.Kind, err)
+		}
+		value.Kind = BooleanValue
+		value.Raw = fmt.Sprint(b)
+	case "NullValue":
+		value.Kind = NullValue
+		value.Raw = "null"
+	case "EnumValue":
+		value.Kind = EnumValue
+		value.Raw = d.string(node)
+	case "ListValue":
+		value.Kind = ListValue
+		for _, item := range node.Values {
+			value.Children = append(value.Children, &ChildValue{Value: d.value(item)})
+		}
+	case "ObjectValue":
+		value.Kind = ObjectValue
+		for _, field := range node.Fields {
+			if !d.expect(field, "ObjectField") {
+				continue
+			}
+			value.Children = append(value.Children, &ChildValue{
+				Name:     d.name(field.Name),
+				Value:    d.value(d.child(field)),
+				Position: d.position(field.Loc),
+			})
+		}
+	default:
+		d.unexpected(node, "a value")
+	}
+	return value
+}
+
+func (d *jsDecoder) typ(node *jsInput) *Type {
+	if node == nil {
+		d.error("missing type")
+		return nil
+	}
+	switch node.Kind {
+	case "NonNullType":
+		typ := d.typ(node.Type)
+		if typ != nil {
+			typ.NonNull = true
+		}
+		return typ
+	case "ListType":
+		return &Type{Elem: d.typ(node.Type), Position: d.position(node.Loc)}
+	case "NamedType":
+		return &Type{NamedType: d.name(node.Name), Position: d.position(node.Loc)}
+	}
+	d.unexpected(node, "NonNullType, ListType or NamedType")
+	return nil
+}
+
+func (d *jsDecoder) schemaDefinition(node *jsInput) *SchemaDefinition {
+	def := &SchemaDefinition{
+		Description: d.description(node.Description),
+		Directives:  d.directives(node.Directives),
+		Position:    d.position(node.Loc),
+	}
+	for _, operationType := range node.OperationTypes {
+		if !d.expect(operationType, "OperationTypeDefinition") {
+			continue
+		}
+		def.OperationTypes = append(def.OperationTypes, &OperationTypeDefinition{
+			Operation: d.operation(operationType),
+			Type:      d.namedType(operationType.Type),
+			Position:  d.position(operationType.Loc),
+		})
+	}
+	return def
+}
+
+func (d *jsDecoder) directiveDefinition(node *jsInput) *DirectiveDefinition {
+	def := &DirectiveDefinition{
+		Description:  d.description(node.Description),
+		Name:         d.name(node.Name),
+		Arguments:    d.inputValueDefinitions(node.Arguments),
+		IsRepeatable: node.Repeatable,
+		Position:     d.position(node.Loc),
+	}
+	for _, location := range node.Locations {
+		def.Locations = append(def.Locations, DirectiveLocation(d.name(location)))
+	}
+	return def
+}
+
+// definition decodes a type definition or extension, which kind is the
+// graphql-js kind without its TypeDefinition or TypeExtension suffix.
+func (d *jsDecoder) definition(node *jsInput, kind string) *Definition {
+	def := &Definition{
+		Description: d.description(node.Description),
+		Name:        d.name(node.Name),
+		Directives:  d.directives(node.Directives),
+		Position:    d.position(node.Loc),
+	}
+	if d.src != nil {
+		def.BuiltIn = d.src.BuiltIn
+	}
+	for k, name := range jsDefinitionKinds {
+		if name == kind {
+			def.Kind = k
+		}
+	}
+
+	switch def.Kind {
+	case Scalar:
+	case Object, Interface:
+		for _, iface := range node.Interfaces {
+			def.Interfaces = append(def.Interfaces, d.namedType(iface))
+		}
+		for _, field := range node.Fields {
+			if !d.expect(field, "FieldDefinition") {
+				continue
+			}
+			def.Fields = append(def.Fields, &FieldDefinition{
+				Description: d.description(field.Description),
+				Name:        d.name(field.Name),
+				Arguments:   d.inputValueDefinitions(field.Arguments),
+				Type:        d.typ(field.Type),
+				Directives:  d.directives(field.Directives),
+				Position:    d.position(field.Loc),
+			})
+		}
+	case Union:
+		for _, member := range node.Types {
+			if !d.expect(member, "NamedType") {
+				continue
+			}
+			def.Types = append(def.Types, d.namedType(member))
+			def.TypePositions = append(def.TypePositions, d.position(member.Loc))
+		}
+	case Enum:
+		for _, value := range node.Values {
+			if !d.expect(value, "EnumValueDefinition") {
+				continue
+			}
+			def.EnumValues = append(def.EnumValues, &EnumValueDefinition{
+				Description: d.description(value.Description),
+				Name:        d.name(value.Name),
+				Directives:  d.directives(value.Directives),
+				Position:    d.position(value.Loc),
+			})
+		}
+	case InputObject:
+		for _, arg := range d.inputValueDefinitions(node.Fields) {
+			def.Fields = append(def.Fields, &FieldDefinition{
+				Description:  arg.Description,
+				Name:         arg.Name,
+				DefaultValue: arg.DefaultValue,
+				Type:         arg.Type,
+				Directives:   arg.Directives,
+				Position:     arg.Position,
+			})
+		}
+	default:
+		d.unexpected(node, "a type system definition")
+	}
+	return def
+}
+
+func (d *jsDecoder) inputValueDefinitions(nodes []*jsInput) ArgumentDefinitionList {
+	var defs ArgumentDefinitionList
+	for _, node := range nodes {
+		if !d.expect(node, "InputValueDefinition") {
+			continue
+		}
+		def := &ArgumentDefinition{
+			Description: d.description(node.Description),
+			Name:        d.name(node.Name),
+			Type:        d.typ(node.Type),
+			Directives:  d.directives(node.Directives),
+			Position:    d.position(node.Loc),
+		}
+		if node.DefaultValue != nil {
+			def.DefaultValue = d.value(node.DefaultValue)
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
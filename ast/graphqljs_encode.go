@@ -0,0 +1,411 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// MarshalGraphQLJS encodes the document as JSON in the shape of the graphql-js
+// AST, e.g. {"kind":"Document","definitions":[...]}, so that it can be handed
+// to JavaScript tooling. UnmarshalQueryDocumentGraphQLJS decodes it.
+//
+// Positions are written to the loc of each node. As in this package, they
+// cover the first token of the node rather than the whole of it, and they also
+// hold the line and column. Comments are not written.
+func (d *QueryDocument) MarshalGraphQLJS() ([]byte, error) {
+	definitions := make([]*jsObject, 0, len(d.Operations)+len(d.Fragments))
+	for _, operation := range d.Operations {
+		definitions = append(definitions, jsOperationDefinition(operation))
+	}
+	for _, fragment := range d.Fragments {
+		definitions = append(definitions, jsFragmentDefinition(fragment))
+	}
+	return json.Marshal(jsNode("Document", d.Position).set("definitions", definitions))
+}
+
+// MarshalGraphQLJS encodes the document as JSON in the shape of the graphql-js
+// AST, like QueryDocument.MarshalGraphQLJS does. The definitions are written
+// by kind: schema definitions and extensions first, then directive
+// definitions, type definitions and type extensions.
+// UnmarshalSchemaDocumentGraphQLJS decodes it.
+func (d *SchemaDocument) MarshalGraphQLJS() ([]byte, error) {
+	var definitions []*jsObject
+	for _, schema := range d.Schema {
+		definitions = append(definitions, jsSchemaDefinition(schema, "SchemaDefinition"))
+	}
+	for _, schema := range d.SchemaExtension {
+		definitions = append(definitions, jsSchemaDefinition(schema, "SchemaExtension"))
+	}
+	for _, directive := range d.Directives {
+		definitions = append(definitions, jsDirectiveDefinition(directive))
+	}
+	for _, def := range d.Definitions {
+		definitions = append(definitions, jsDefinition(def, false))
+	}
+	for _, def := range d.Extensions {
+		definitions = append(definitions, jsDefinition(def, true))
+	}
+	if definitions == nil {
+		definitions = []*jsObject{}
+	}
+	return json.Marshal(jsNode("Document", d.Position).set("definitions", definitions))
+}
+
+// jsObject is a JSON object that keeps its keys in the order they are set.
+type jsObject struct {
+	keys   []string
+	values []any
+}
+
+// jsNode starts a graphql-js AST node of kind, located at pos.
+func jsNode(kind string, pos *Position) *jsObject {
+	node := (&jsObject{}).set("kind", kind)
+	if pos != nil {
+		node.set("loc", jsLocation{
+			Start:  pos.Start,
+			End:    pos.End,
+			Line:   pos.Line,
+			Column: pos.Column,
+		})
+	}
+	return node
+}
+
+type jsLocation struct {
+	Start  int `json:"start"`
+	End    int `json:"end"`
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+func (o *jsObject) set(key string, value any) *jsObject {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+	return o
+}
+
+func (o *jsObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func jsName(name string) *jsObject {
+	return jsNode("Name", nil).set("value", name)
+}
+
+func jsNamedType(name string, pos *Position) *jsObject {
+	return jsNode("NamedType", pos).set("name", jsName(name))
+}
+
+func jsNamedTypes(names []string, positions []*Position) []*jsObject {
+	types := make([]*jsObject, 0, len(names))
+	for i, name := range names {
+		var pos *Position
+		if i < len(positions) {
+			pos = positions[i]
+		}
+		types = append(types, jsNamedType(name, pos))
+	}
+	return types
+}
+
+func jsDescription(node *jsObject, description string) {
+	if description == "" {
+		return
+	}
+	node.set("description", jsNode("StringValue", nil).
+		set("value", description).
+		set("block", strings.Contains(description, "\n")))
+}
+
+func jsOperationDefinition(operation *OperationDefinition) *jsObject {
+	node := jsNode("OperationDefinition", operation.Position)
+	op := operation.Operation
+	if op == "" {
+		op = Query
+	}
+	node.set("operation", op)
+	if operation.Name != "" {
+		node.set("name", jsName(operation.Name))
+	}
+	return node.
+		set("variableDefinitions", jsVariableDefinitions(operation.VariableDefinitions)).
+		set("directives", jsDirectives(operation.Directives)).
+		set("selectionSet", jsSelectionSet(operation.SelectionSet))
+}
+
+func jsFragmentDefinition(fragment *FragmentDefinition) *jsObject {
+	node := jsNode("FragmentDefinition", fragment.Position).set("name", jsName(fragment.Name))
+	if len(fragment.VariableDefinition) > 0 {
+		node.set("variableDefinitions", jsVariableDefinitions(fragment.VariableDefinition))
+	}
+	return node.
+		set("typeCondition", jsNamedType(fragment.TypeCondition, nil)).
+		set("directives", jsDirectives(fragment.Directives)).
+		set("selectionSet", jsSelectionSet(fragment.SelectionSet))
+}
+
+func jsVariableDefinitions(variables VariableDefinitionList) []*jsObject {
+	nodes := make([]*jsObject, 0, len(variables))
+	for _, variable := range variables {
+		node := jsNode("VariableDefinition", variable.Position).
+			set("variable", jsNode("Variable", nil).set("name", jsName(variable.Variable))).
+			set("type", jsType(variable.Type))
+		if variable.DefaultValue != nil {
+			node.set("defaultValue", jsValue(variable.DefaultValue))
+		}
+		nodes = append(nodes, node.set("directives", jsDirectives(variable.Directives)))
+	}
+	return nodes
+}
+
+func jsSelectionSet(selectionSet SelectionSet) *jsObject {
+	selections := make([]*jsObject, 0, len(selectionSet))
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *Field:
+			node := jsNode("Field", selection.Position)
+			if selection.Alias != "" && selection.Alias != selection.Name {
+				node.set("alias", jsName(selection.Alias))
+			}
+			node.
+				set("name", jsName(selection.Name)).
+				set("arguments", jsArguments(selection.Arguments)).
+				set("directives", jsDirectives(selection.Directives))
+			if len(selection.SelectionSet) > 0 {
+				node.set("selectionSet", jsSelectionSet(selection.SelectionSet))
+			}
+			selections = append(selections, node)
+		case *FragmentSpread:
+			selections = append(selections, jsNode("FragmentSpread", selection.Position).
+				set("name", jsName(selection.Name)).
+				set("directives", jsDirectives(selection.Directives)))
+		case *InlineFragment:
+			node := jsNode("InlineFragment", selection.Position)
+			if selection.TypeCondition != "" {
+				node.set("typeCondition", jsNamedType(selection.TypeCondition, nil))
+			}
+			selections = append(selections, node.
+				set("directives", jsDirectives(selection.Directives)).
+				set("selectionSet", jsSelectionSet(selection.SelectionSet)))
+		}
+	}
+	return jsNode("SelectionSet", nil).set("selections", selections)
+}
+
+func jsArguments(arguments ArgumentList) []*jsObject {
+	nodes := make([]*jsObject, 0, len(arguments))
+	for _, argument := range arguments {
+		nodes = append(nodes, jsNode("Argument", argument.Position).
+			set("name", jsName(argument.Name)).
+			set("value", jsValue(argument.Value)))
+	}
+	return nodes
+}
+
+func jsDirectives(directives DirectiveList) []*jsObject {
+	nodes := make([]*jsObject, 0, len(directives))
+	for _, directive := range directives {
+		nodes = append(nodes, jsNode("Directive", directive.Position).
+			set("name", jsName(directive.Name)).
+			set("arguments", jsArguments(directive.Arguments)))
+	}
+	return nodes
+}
+
+func jsValue(value *Value) *jsObject {
+	switch value.Kind {
+	case Variable:
+		return jsNode("Variable", value.Position).set("name", jsName(value.Raw))
+	case IntValue:
+		return jsNode("IntValue", value.Position).set("value", value.Raw)
+	case FloatValue:
+		return jsNode("FloatValue", value.Position).set("value", value.Raw)
+	case StringValue, BlockValue:
+		return jsNode("StringValue", value.Position).
+			set("value", value.Raw).
+			set("block", value.Kind == BlockValue)
+	case BooleanValue:
+		return jsNode("BooleanValue", value.Position).set("value", value.Raw == "true")
+	case NullValue:
+		return jsNode("NullValue", value.Position)
+	case EnumValue:
+		return jsNode("EnumValue", value.Position).set("value", value.Raw)
+	case ListValue:
+		values := make([]*jsObject, 0, len(value.Children))
+		for _, child := range value.Children {
+			values = append(values, jsValue(child.Value))
+		}
+		return jsNode("ListValue", value.Position).set("values", values)
+	default:
+		fields := make([]*jsObject, 0, len(value.Children))
+		for _, child := range value.Children {
+			fields = append(fields, jsNode("ObjectField", child.Position).
+				set("name", jsName(child.Name)).
+				set("value", jsValue(child.Value)))
+		}
+		return jsNode("ObjectValue", value.Position).set("fields", fields)
+	}
+}
+
+func jsType(typ *Type) *jsObject {
+	var node *jsObject
+	if typ.Elem != nil {
+		node = jsNode("ListType", typ.Position).set("type", jsType(typ.Elem))
+	} else {
+		node = jsNamedType(typ.NamedType, typ.Position)
+	}
+	if typ.NonNull {
+		node = jsNode("NonNullType", typ.Position).set("type", node)
+	}
+	return node
+}
+
+func jsSchemaDefinition(schema *SchemaDefinition, kind string) *jsObject {
+	node := jsNode(kind, schema.Position)
+	jsDescription(node, schema.Description)
+	operationTypes := make([]*jsObject, 0, len(schema.OperationTypes))
+	for _, operationType := range schema.OperationTypes {
+		operationTypes = append(operationTypes,
+			jsNode("OperationTypeDefinition", operationType.Position).
+				set("operation", operationType.Operation).
+				set("type", jsNamedType(operationType.Type, nil)))
+	}
+	return node.
+		set("directives", jsDirectives(schema.Directives)).
+		set("operationTypes", operationTypes)
+}
+
+func jsDirectiveDefinition(directive *DirectiveDefinition) *jsObject {
+	node := jsNode("DirectiveDefinition", directive.Position)
+	jsDescription(node, directive.Description)
+	locations := make([]*jsObject, 0, len(directive.Locations))
+	for _, location := range directive.Locations {
+		locations = append(locations, jsName(string(location)))
+	}
+	return node.
+		set("name", jsName(directive.Name)).
+		set("arguments", jsInputValueDefinitions(directive.Arguments)).
+		set("repeatable", directive.IsRepeatable).
+		set("locations", locations)
+}
+
+// jsDefinitionKinds names the graphql-js kinds of definitions, without their
+// TypeDefinition or TypeExtension suffix.
+var jsDefinitionKinds = map[DefinitionKind]string{
+	Scalar:      "Scalar",
+	Object:      "Object",
+	Interface:   "Interface",
+	Union:       "Union",
+	Enum:        "Enum",
+	InputObject: "InputObject",
+}
+
+func jsDefinition(def *Definition, extension bool) *jsObject {
+	kind := jsDefinitionKinds[def.Kind] + "TypeDefinition"
+	if extension {
+		kind = jsDefinitionKinds[def.Kind] + "TypeExtension"
+	}
+	node := jsNode(kind, def.Position)
+	jsDescription(node, def.Description)
+	node.set("name", jsName(def.Name))
+
+	switch def.Kind {
+	case Object, Interface:
+		node.
+			set("interfaces", jsNamedTypes(def.Interfaces, nil)).
+			set("directives", jsDirectives(def.Directives))
+		fields := make([]*jsObject, 0, len(def.Fields))
+		for _, field := range def.Fields {
+			fieldNode := jsNode("FieldDefinition", field.Position)
+			jsDescription(fieldNode, field.Description)
+			fields = append(fields, fieldNode.
+				set("name", jsName(field.Name)).
+				set("arguments", jsInputValueDefinitions(field.Arguments)).
+				set("type", jsType(field.Type)).
+				set("directives", jsDirectives(field.Directives)))
+		}
+		node.set("fields", fields)
+	case Union:
+		node.
+			set("directives", jsDirectives(def.Directives)).
+			set("types", jsNamedTypes(def.Types, def.TypePositions))
+	case Enum:
+		values := make([]*jsObject, 0, len(def.EnumValues))
+		for _, value := range def.EnumValues {
+			valueNode := jsNode("EnumValueDefinition", value.Position)
+			jsDescription(valueNode, value.Description)
+			values = append(values, valueNode.
+				set("name", jsName(value.Name)).
+				set("directives", jsDirectives(value.Directives)))
+		}
+		node.set("directives", jsDirectives(def.Directives)).set("values", values)
+	case InputObject:
+		fields := make([]*jsObject, 0, len(def.Fields))
+		for _, field := range def.Fields {
+			fields = append(fields, jsInputValueDefinition(
+				field.Position,
+				field.Description,
+				field.Name,
+				field.Type,
+				field.DefaultValue,
+				field.Directives,
+			))
+		}
+		node.set("directives", jsDirectives(def.Directives)).set("fields", fields)
+	default:
+		node.set("directives", jsDirectives(def.Directives))
+	}
+	return node
+}
+
+func jsInputValueDefinitions(arguments ArgumentDefinitionList) []*jsObject {
+	nodes := make([]*jsObject, 0, len(arguments))
+	for _, argument := range arguments {
+		nodes = append(nodes, jsInputValueDefinition(
+			argument.Position,
+			argument.Description,
+			argument.Name,
+			argument.Type,
+			argument.DefaultValue,
+			argument.Directives,
+		))
+	}
+	return nodes
+}
+
+func jsInputValueDefinition(
+	pos *Position,
+	description string,
+	name string,
+	typ *Type,
+	defaultValue *Value,
+	directives DirectiveList,
+) *jsObject {
+	node := jsNode("InputValueDefinition", pos)
+	jsDescription(node, description)
+	node.set("name", jsName(name)).set("type", jsType(typ))
+	if defaultValue != nil {
+		node.set("defaultValue", jsValue(defaultValue))
+	}
+	return node.set("directives", jsDirectives(directives))
+}
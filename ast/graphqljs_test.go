@@ -0,0 +1,239 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestGraphQLJS(t *testing.T) {
+	t.Run("query document", func(t *testing.T) {
+		src := &Source{Name: "query.graphql", Input: `
+			query Pets($first: Int = 10, $filter: Filter @deprecated) @live {
+				pets(first: $first, filter: $filter, tags: ["a", """b"""],
+						near: {lat: 1.5, x: null}) {
+					alias: name @include(if: true)
+					... on Dog { barks }
+					...Named
+				}
+			}
+			mutation { adopt(kind: DOG) }
+			fragment Named on Pet @skip(if: false) { name }
+		`}
+		doc, err := parser.ParseQuery(src)
+		require.NoError(t, err)
+
+		data, err := doc.MarshalGraphQLJS()
+		require.NoError(t, err)
+		decoded, err := UnmarshalQueryDocumentGraphQLJS(data, src)
+		require.NoError(t, err)
+
+		equal, diff := Equal(doc, decoded, EqualIgnoreComments())
+		require.True(t, equal, diff)
+	})
+
+	t.Run("schema document", func(t *testing.T) {
+		src := &Source{Name: "schema.graphql", Input: `
+			schema @key { query: Query }
+			extend schema { mutation: Mutation }
+			"Marks things"
+			directive @mark(reason: String = "because") repeatable on FIELD_DEFINITION | OBJECT
+			"""
+			The root
+			"""
+			type Query implements Node & Named @mark {
+				"The pets"
+				pets(first: Int! = 10, kinds: [Kind!]): [Pet]! @deprecated
+			}
+			interface Node { id: ID! }
+			union Pet = Dog | Cat
+			enum Kind { "A dog" DOG @mark CAT }
+			input Filter { name: String = "rex" @mark, kinds: [Kind] }
+			scalar Date @specifiedBy(url: "https://example.com")
+			extend type Query { date: Date }
+		`}
+		doc, err := parser.ParseSchema(src)
+		require.NoError(t, err)
+
+		data, err := doc.MarshalGraphQLJS()
+		require.NoError(t, err)
+		decoded, err := UnmarshalSchemaDocumentGraphQLJS(data, src)
+		require.NoError(t, err)
+
+		equal, diff := Equal(doc, decoded, EqualIgnoreComments())
+		require.True(t, equal, diff)
+	})
+
+	t.Run("shape", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&Source{Input: `{ a: b(x: 1) }`})
+		require.NoError(t, err)
+		data, err := doc.MarshalGraphQLJS()
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{
+			"kind": "Field",
+			"loc": {"start": 2, "end": 3, "line": 1, "column": 3},
+			"alias": {"kind": "Name", "value": "a"},
+			"name": {"kind": "Name", "value": "b"},
+			"arguments": [{
+				"kind": "Argument",
+				"loc": {"start": 7, "end": 8, "line": 1, "column": 8},
+				"name": {"kind": "Name", "value": "x"},
+				"value": {
+					"kind": "IntValue",
+					"loc": {"start": 10, "end": 11, "line": 1, "column": 11},
+					"value": "1"
+				}
+			}],
+			"directives": []
+		}`, firstSelection(t, data))
+	})
+
+	t.Run("graphql-js locations", func(t *testing.T) {
+		src := &Source{Input: "{\n  a\n}"}
+		decoded, err := UnmarshalQueryDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [{
+				"kind": "OperationDefinition",
+				"operation": "query",
+				"selectionSet": {
+					"kind": "SelectionSet",
+					"selections": [{"kind": "Field", "name": {"kind": "Name", "value": "a"},
+						"loc": {"start": 4, "end": 5}}]
+				}
+			}]
+		}`), src)
+		require.NoError(t, err)
+		field := decoded.Operations[0].SelectionSet[0].(*Field)
+		require.Equal(t, "a", field.Alias)
+		require.Equal(t, &Position{Start: 4, End: 5, Line: 2, Column: 3, Src: src}, field.Position)
+
+		// Lines break on "\r\n" and "\r" as well, and offsets count runes.
+		src = &Source{Input: "{\r\n  é\r\r  b\n  c\n}"}
+		decoded, err = UnmarshalQueryDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [{
+				"kind": "OperationDefinition",
+				"operation": "query",
+				"selectionSet": {
+					"kind": "SelectionSet",
+					"selections": [
+						{"kind": "Field", "name": {"kind": "Name", "value": "é"},
+							"loc": {"start": 5, "end": 6}},
+						{"kind": "Field", "name": {"kind": "Name", "value": "b"},
+							"loc": {"start": 10, "end": 11}},
+						{"kind": "Field", "name": {"kind": "Name", "value": "c"},
+							"loc": {"start": 14, "end": 15}}
+					]
+				}
+			}]
+		}`), src)
+		require.NoError(t, err)
+		var lines [][2]int
+		for _, selection := range decoded.Operations[0].SelectionSet {
+			pos := selection.GetPosition()
+			lines = append(lines, [2]int{pos.Line, pos.Column})
+		}
+		require.Equal(t, [][2]int{{2, 3}, {4, 3}, {5, 3}}, lines)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := UnmarshalQueryDocumentGraphQLJS([]byte(`{"kind": "Field"}`), nil)
+		require.EqualError(t, err, `unexpected kind "Field", expected Document`)
+
+		_, err = UnmarshalQueryDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [{"kind": "ObjectTypeDefinition"}]
+		}`), nil)
+		require.EqualError(t, err, `unexpected kind "ObjectTypeDefinition", `+
+			`expected OperationDefinition or FragmentDefinition`)
+
+		_, err = UnmarshalSchemaDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [{"kind": "ScalarTypeDefinition"}]
+		}`), nil)
+		require.EqualError(t, err, "missing Name")
+
+		for _, tc := range []struct{ input, err string }{
+			{
+				input: `{"kind": "Document", "definitions": [null]}`,
+				err:   "missing OperationDefinition or FragmentDefinition",
+			},
+			{
+				input: `{"kind": "Document", "definitions": [{
+					"kind": "OperationDefinition",
+					"operation": "query",
+					"selectionSet": {"kind": "SelectionSet", "selections": [null]}
+				}]}`,
+				err: "missing Field, FragmentSpread or InlineFragment",
+			},
+			{
+				input: `{"kind": "Document", "definitions": [{
+					"kind": "OperationDefinition",
+					"operation": "query",
+					"selectionSet": {"kind": "SelectionSet", "selections": [{
+						"kind": "Field",
+						"name": {"kind": "Name", "value": "a"},
+						"arguments": [{
+							"kind": "Argument",
+							"name": {"kind": "Name", "value": "x"},
+							"value": {"kind": "ListValue", "values": [null]}
+						}]
+					}]}
+				}]}`,
+				err: "missing value",
+			},
+			{
+				input: `{"kind": "Document", "definitions": [{
+					"kind": "OperationDefinition",
+					"operation": "drop",
+					"selectionSet": {"kind": "SelectionSet", "selections": []}
+				}]}`,
+				err: `unexpected operation "drop" of OperationDefinition, ` +
+					`expected query, mutation or subscription`,
+			},
+			{
+				input: `{"kind": "Document", "loc": {"start": -1, "end": 0}}`,
+				err:   "invalid loc start -1",
+			},
+		} {
+			_, err = UnmarshalQueryDocumentGraphQLJS([]byte(tc.input), &Source{})
+			require.EqualError(t, err, tc.err, tc.input)
+		}
+
+		_, err = UnmarshalSchemaDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [null]
+		}`), nil)
+		require.EqualError(t, err, "missing type system definition")
+
+		_, err = UnmarshalSchemaDocumentGraphQLJS([]byte(`{
+			"kind": "Document",
+			"definitions": [{"kind": "SchemaDefinition", "operationTypes": [{
+				"kind": "OperationTypeDefinition",
+				"operation": "query { a }",
+				"type": {"kind": "NamedType", "name": {"kind": "Name", "value": "Query"}}
+			}]}]
+		}`), nil)
+		require.EqualError(t, err, `unexpected operation "query { a }" of `+
+			`OperationTypeDefinition, expected query, mutation or subscription`)
+	})
+}
+
+// firstSelection returns the first selection of the first definition in data.
+func firstSelection(t *testing.T, data []byte) string {
+	t.Helper()
+	var doc struct {
+		Definitions []struct {
+			SelectionSet struct {
+				Selections []json.RawMessage `json:"selections"`
+			} `json:"selectionSet"`
+		} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return string(doc.Definitions[0].SelectionSet.Selections[0])
+}
@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ResultMap is an object in the data of an execution result. Its fields are
+// kept in the order they were selected, which is the order the spec requires
+// them to be serialized in, so it marshals to a JSON object in that order.
+// A nil ResultMap marshals to null.
+type ResultMap []ResultField
+
+// ResultField is the value of a response key in a ResultMap.
+type ResultField struct {
+	Key   string
+	Value any
+}
+
+var _ json.Marshaler = ResultMap(nil)
+
+// Get returns the value of key, and whether it is in m.
+func (m ResultMap) Get(key string) (any, bool) {
+	for _, f := range m {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the response keys of m in order.
+func (m ResultMap) Keys() []string {
+	keys := make([]string, len(m))
+	for i, f := range m {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+func (m ResultMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range m {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultMap(t *testing.T) {
+	m := ResultMap{
+		{Key: "zebra", Value: 1},
+		{Key: "apple", Value: ResultMap{{Key: "y", Value: nil}, {Key: "x", Value: []any{"a"}}}},
+		{Key: "mango", Value: ResultMap(nil)},
+	}
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"zebra":1,"apple":{"y":null,"x":["a"]},"mango":null}`, string(data))
+
+	require.Equal(t, []string{"zebra", "apple", "mango"}, m.Keys())
+	value, ok := m.Get("zebra")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+	_, ok = m.Get("kiwi")
+	require.False(t, ok)
+
+	data, err = json.Marshal(ResultMap{})
+	require.NoError(t, err)
+	require.Equal(t, `{}`, string(data))
+}
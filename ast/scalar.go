@@ -0,0 +1,47 @@
+package ast
+
+// ScalarParser checks the values given to a custom scalar. Either function may
+// be nil to accept anything, which is what happens to custom scalars that have
+// no parser.
+type ScalarParser struct {
+	// ParseLiteral checks a literal value of the scalar in a document. It is
+	// not given variables or null.
+	ParseLiteral func(value *Value) error
+	// ParseValue checks the value of a variable of the scalar, and returns it
+	// coerced.
+	ParseValue func(value any) (any, error)
+}
+
+// ScalarRegistry maps the name of a custom scalar, or the URL given to
+// @specifiedBy on it, to its parser.
+type ScalarRegistry map[string]*ScalarParser
+
+// ForDefinition is the parser of the scalar def, looked up by name and then by
+// its @specifiedBy URL.
+func (r ScalarRegistry) ForDefinition(def *Definition) *ScalarParser {
+	if r == nil || def == nil {
+		return nil
+	}
+	if parser := r[def.Name]; parser != nil {
+		return parser
+	}
+	if specifiedBy := def.Directives.ForName("specifiedBy"); specifiedBy != nil {
+		if url := specifiedBy.Arguments.ForName("url"); url != nil && url.Value != nil {
+			return r[url.Value.Raw]
+		}
+	}
+	return nil
+}
+
+// RegisterScalar sets the parser of the scalar with the given name, or of
+// every scalar specified by the given URL.
+func (s *Schema) RegisterScalar(
+	nameOrURL string,
+	parseLiteral func(value *Value) error,
+	parseValue func(value any) (any, error),
+) {
+	if s.Scalars == nil {
+		s.Scalars = ScalarRegistry{}
+	}
+	s.Scalars[nameOrURL] = &ScalarParser{ParseLiteral: parseLiteral, ParseValue: parseValue}
+}
@@ -24,7 +24,7 @@ type Type struct {
 }
 
 func (t *Type) Name() string {
-	if t.NamedType != "" {
+	if t.NamedType != "" || t.Elem == nil {
 		return t.NamedType
 	}
 
@@ -36,7 +36,7 @@ func (t *Type) String() string {
 	if t.NonNull {
 		nn = "!"
 	}
-	if t.NamedType != "" {
+	if t.NamedType != "" || t.Elem == nil {
 		return t.NamedType + nn
 	}
 
@@ -0,0 +1,267 @@
+package ast
+
+// TypeInfo tracks what the schema says about the node being visited: the
+// types, field, directive, argument and enum value it refers to. Unlike the
+// validator, it works out this information as it goes and never writes it
+// into the document, so it can be used on documents that were not validated.
+//
+// Use it with VisitWithTypeInfo, or call Enter and Leave around each node of
+// another traversal.
+type TypeInfo struct {
+	schema *Schema
+	frames []typeInfoFrame
+}
+
+// typeInfoFrame holds what is known at one node. Nodes inherit everything
+// their parent knows, except where they say otherwise.
+type typeInfoFrame struct {
+	node            any
+	typ             *Type
+	parentType      *Definition
+	fieldDef        *FieldDefinition
+	inputType       *Type
+	parentInputType *Type
+	defaultValue    *Value
+	directive       *DirectiveDefinition
+	argument        *ArgumentDefinition
+	enumValue       *EnumValueDefinition
+}
+
+func NewTypeInfo(schema *Schema) *TypeInfo {
+	return &TypeInfo{schema: schema}
+}
+
+func (ti *TypeInfo) top() typeInfoFrame {
+	if len(ti.frames) == 0 {
+		return typeInfoFrame{}
+	}
+	return ti.frames[len(ti.frames)-1]
+}
+
+// Type is the output type of the current operation, fragment or field.
+func (ti *TypeInfo) Type() *Type {
+	return ti.top().typ
+}
+
+// ParentType is the type of the selection set holding the current selection.
+func (ti *TypeInfo) ParentType() *Definition {
+	return ti.top().parentType
+}
+
+// FieldDef is the definition of the current field.
+func (ti *TypeInfo) FieldDef() *FieldDefinition {
+	return ti.top().fieldDef
+}
+
+// InputType is the type expected for the current variable, argument or value.
+func (ti *TypeInfo) InputType() *Type {
+	return ti.top().inputType
+}
+
+// ParentInputType is the type expected for the value holding the current
+// one, e.g. the list type of a list item.
+func (ti *TypeInfo) ParentInputType() *Type {
+	return ti.top().parentInputType
+}
+
+// DefaultValue is the default of the current argument or input field, which
+// is used when no value is given.
+func (ti *TypeInfo) DefaultValue() *Value {
+	return ti.top().defaultValue
+}
+
+// Directive is the definition of the current directive.
+func (ti *TypeInfo) Directive() *DirectiveDefinition {
+	return ti.top().directive
+}
+
+// Argument is the definition of the current field or directive argument.
+func (ti *TypeInfo) Argument() *ArgumentDefinition {
+	return ti.top().argument
+}
+
+// EnumValue is the definition of the current enum value.
+func (ti *TypeInfo) EnumValue() *EnumValueDefinition {
+	return ti.top().enumValue
+}
+
+// Enter moves into node, which must be a child of the node last entered.
+func (ti *TypeInfo) Enter(node any) {
+	parent := ti.top()
+	frame := parent
+	frame.node = node
+
+	switch node := node.(type) {
+	case *OperationDefinition:
+		frame = typeInfoFrame{node: node}
+		if def := ti.rootType(node.Operation); def != nil {
+			frame.typ = NamedType(def.Name, nil)
+		}
+	case *FragmentDefinition:
+		frame = typeInfoFrame{node: node, typ: ti.namedType(node.TypeCondition)}
+	case *Field:
+		frame = typeInfoFrame{node: node, parentType: ti.compositeType(parent.typ)}
+		frame.fieldDef = ti.fieldDef(frame.parentType, node.Name)
+		if frame.fieldDef != nil {
+			frame.typ = frame.fieldDef.Type
+		}
+	case *InlineFragment:
+		frame = typeInfoFrame{node: node, parentType: ti.compositeType(parent.typ)}
+		if node.TypeCondition != "" {
+			frame.typ = ti.namedType(node.TypeCondition)
+		} else if parent.typ != nil {
+			frame.typ = NamedType(parent.typ.Name(), nil)
+		}
+	case *FragmentSpread:
+		frame = typeInfoFrame{
+			node:       node,
+			typ:        parent.typ,
+			parentType: ti.compositeType(parent.typ),
+		}
+	case *VariableDefinition:
+		frame.setInputType(node.Type)
+	case *Directive:
+		frame.directive = ti.schema.Directives[node.Name]
+	case *Argument:
+		var arguments ArgumentDefinitionList
+		switch {
+		case parent.directive != nil:
+			arguments = parent.directive.Arguments
+		case parent.fieldDef != nil:
+			arguments = parent.fieldDef.Arguments
+		}
+		frame.argument = arguments.ForName(node.Name)
+		var inputType *Type
+		frame.defaultValue = nil
+		if frame.argument != nil {
+			inputType = frame.argument.Type
+			frame.defaultValue = frame.argument.DefaultValue
+		}
+		frame.setInputType(inputType)
+	case *ChildValue:
+		var inputType *Type
+		frame.defaultValue = nil
+		value, _ := parent.node.(*Value)
+		switch {
+		case value == nil || parent.inputType == nil:
+		case value.Kind == ListValue:
+			inputType = listItemType(parent.inputType)
+		case value.Kind == ObjectValue:
+			def := ti.schema.Types[parent.inputType.Name()]
+			if def == nil || def.Kind != InputObject {
+				break
+			}
+			if fieldDef := def.Fields.ForName(node.Name); fieldDef != nil {
+				inputType = fieldDef.Type
+				frame.defaultValue = fieldDef.DefaultValue
+			}
+		}
+		frame.setInputType(inputType)
+	case *Value:
+		frame.enumValue = nil
+		if node.Kind == EnumValue && frame.inputType != nil {
+			if def := ti.schema.Types[frame.inputType.Name()]; def != nil && def.Kind == Enum {
+				frame.enumValue = def.EnumValues.ForName(node.Raw)
+			}
+		}
+	}
+
+	ti.frames = append(ti.frames, frame)
+}
+
+// Leave moves out of the node last entered.
+func (ti *TypeInfo) Leave(node any) {
+	if len(ti.frames) > 0 {
+		ti.frames = ti.frames[:len(ti.frames)-1]
+	}
+}
+
+func (f *typeInfoFrame) setInputType(typ *Type) {
+	f.parentInputType = f.inputType
+	f.inputType = typ
+}
+
+func (ti *TypeInfo) rootType(operation Operation) *Definition {
+	switch operation {
+	case Query, "":
+		return ti.schema.Query
+	case Mutation:
+		return ti.schema.Mutation
+	case Subscription:
+		return ti.schema.Subscription
+	}
+	return nil
+}
+
+func (ti *TypeInfo) namedType(name string) *Type {
+	if ti.schema.Types[name] == nil {
+		return nil
+	}
+	return NamedType(name, nil)
+}
+
+func (ti *TypeInfo) compositeType(typ *Type) *Definition {
+	if typ == nil {
+		return nil
+	}
+	def := ti.schema.Types[typ.Name()]
+	if def == nil || !def.IsCompositeType() {
+		return nil
+	}
+	return def
+}
+
+func (ti *TypeInfo) fieldDef(parentType *Definition, name string) *FieldDefinition {
+	if parentType == nil {
+		return nil
+	}
+	if name == "__typename" {
+		return &FieldDefinition{Name: "__typename", Type: NonNullNamedType("String", nil)}
+	}
+	return parentType.Fields.ForName(name)
+}
+
+// listItemType is the type expected for the items of a list given where typ
+// is expected. A single value is accepted as a list of one, so the items of a
+// list given for a non-list type are expected to be of that type.
+func listItemType(typ *Type) *Type {
+	if typ.Elem != nil {
+		return typ.Elem
+	}
+	return &Type{NamedType: typ.NamedType, Position: typ.Position}
+}
+
+// VisitWithTypeInfo returns a Visitor that keeps typeInfo in step with the
+// traversal while calling visitor, so that the callbacks of visitor can ask
+// typeInfo about the node they are passed.
+func VisitWithTypeInfo(typeInfo *TypeInfo, visitor Visitor) Visitor {
+	return Visitor{
+		Enter: func(c *Cursor) VisitAction {
+			typeInfo.Enter(c.Node())
+			if visitor.Enter == nil {
+				return VisitContinue
+			}
+
+			action := visitor.Enter(c)
+			switch {
+			case c.replaced:
+				typeInfo.Leave(c.Node())
+				if c.replacement != nil && action == VisitContinue {
+					typeInfo.Enter(c.replacement)
+				}
+			case action == VisitSkip:
+				// Nodes that are skipped are not left.
+				typeInfo.Leave(c.Node())
+			}
+			return action
+		},
+		Leave: func(c *Cursor) VisitAction {
+			action := VisitContinue
+			if visitor.Leave != nil {
+				action = visitor.Leave(c)
+			}
+			typeInfo.Leave(c.Node())
+			return action
+		},
+	}
+}
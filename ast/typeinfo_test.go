@@ -0,0 +1,106 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestTypeInfo(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&Source{Input: `
+		type Query { pet(filter: Filter, tags: [String!] = []): Pet }
+		interface Pet { name: String }
+		type Dog implements Pet { name: String barks(times: Int = 1): Boolean }
+		input Filter { kind: Kind = DOG name: String }
+		enum Kind { DOG CAT }
+	`})
+	doc, err := parser.ParseQuery(&Source{Input: `{
+		pet(filter: {kind: CAT}, tags: ["a"]) {
+			__typename
+			... on Dog { barks @skip(if: true) }
+			...Named
+		}
+	}
+	fragment Named on Pet { name unknown }`})
+	require.NoError(t, err)
+
+	typeName := func(typ *Type) string {
+		if typ == nil {
+			return "<nil>"
+		}
+		return typ.String()
+	}
+	var events []string
+	typeInfo := NewTypeInfo(schema)
+	Visit(doc, VisitWithTypeInfo(typeInfo, Visitor{
+		Enter: func(c *Cursor) VisitAction {
+			var event string
+			switch node := c.Node().(type) {
+			case *Field:
+				fieldDef := "<nil>"
+				if typeInfo.FieldDef() != nil {
+					fieldDef = typeInfo.FieldDef().Name
+				}
+				event = fmt.Sprintf("field %s: %s, def %s",
+					node.Name, typeName(typeInfo.Type()), fieldDef)
+				if typeInfo.ParentType() != nil {
+					event += " in " + typeInfo.ParentType().Name
+				}
+			case *InlineFragment, *FragmentSpread, *FragmentDefinition:
+				event = fmt.Sprintf("%T: %s", node, typeName(typeInfo.Type()))
+				if typeInfo.ParentType() != nil {
+					event += " in " + typeInfo.ParentType().Name
+				}
+			case *Argument:
+				event = fmt.Sprintf("argument %s: %s", node.Name, typeName(typeInfo.InputType()))
+				if typeInfo.Directive() != nil {
+					event += " of @" + typeInfo.Directive().Name
+				}
+				if typeInfo.DefaultValue() != nil {
+					event += " = " + typeInfo.DefaultValue().String()
+				}
+			case *ChildValue:
+				event = fmt.Sprintf("child %q: %s in %s", node.Name,
+					typeName(typeInfo.InputType()), typeName(typeInfo.ParentInputType()))
+				if typeInfo.DefaultValue() != nil {
+					event += " = " + typeInfo.DefaultValue().String()
+				}
+			case *Value:
+				if typeInfo.EnumValue() != nil {
+					event = "enum value " + typeInfo.EnumValue().Name
+				}
+			}
+			if event != "" {
+				events = append(events, event)
+			}
+			return VisitContinue
+		},
+	}))
+
+	require.Equal(t, []string{
+		"field pet: Pet, def pet in Query",
+		"argument filter: Filter",
+		`child "kind": Kind in Filter = DOG`,
+		"enum value CAT",
+		"argument tags: [String!] = []",
+		`child "": String! in [String!]`,
+		"field __typename: String!, def __typename in Pet",
+		"*ast.InlineFragment: Dog in Pet",
+		"field barks: Boolean, def barks in Dog",
+		"argument if: Boolean! of @skip",
+		"*ast.FragmentSpread: Pet in Pet",
+		"*ast.FragmentDefinition: Pet",
+		"field name: String, def name in Pet",
+		"field unknown: <nil>, def <nil> in Pet",
+	}, events)
+
+	pet := doc.Operations[0].SelectionSet[0].(*Field)
+	require.Nil(t, pet.Definition, "the document is not changed")
+	require.Nil(t, pet.ObjectDefinition, "the document is not changed")
+	require.Nil(t, pet.Arguments[0].Value.ExpectedType, "the document is not changed")
+}
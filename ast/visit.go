@@ -0,0 +1,245 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// VisitAction tells Visit how to go on after a Visitor callback returns.
+type VisitAction int
+
+const (
+	// VisitContinue visits the children of a node being entered, then moves
+	// on to the next node.
+	VisitContinue VisitAction = iota
+	// VisitSkip does not visit the children of a node being entered. It is the
+	// same as VisitContinue when leaving a node.
+	VisitSkip
+	// VisitBreak stops visiting. Changes made so far are kept.
+	VisitBreak
+)
+
+// Visitor holds the callbacks used by Visit. Either may be nil.
+type Visitor struct {
+	// Enter is called before the children of a node are visited.
+	Enter func(c *Cursor) VisitAction
+	// Leave is called after the children of a node have been visited, unless
+	// Enter skipped them.
+	Leave func(c *Cursor) VisitAction
+}
+
+// Cursor describes the node being visited, and where it is in the tree. It is
+// only valid during the callback it is passed to.
+type Cursor struct {
+	node      any
+	ancestors []any
+	path      Path
+
+	replacement any
+	replaced    bool
+}
+
+// Node is the node being visited.
+func (c *Cursor) Node() any {
+	return c.node
+}
+
+// Parent is the node holding the one being visited, or nil for the root.
+func (c *Cursor) Parent() any {
+	if len(c.ancestors) == 0 {
+		return nil
+	}
+	return c.ancestors[len(c.ancestors)-1]
+}
+
+// Ancestors are the nodes from the root down to the parent of the node being
+// visited. Lists are not nodes, so they are not included.
+func (c *Cursor) Ancestors() []any {
+	return slices.Clone(c.ancestors)
+}
+
+// Path leads from the root to the node being visited, naming the struct fields
+// and list indexes followed, e.g. Operations[0].SelectionSet[2].
+func (c *Cursor) Path() Path {
+	return slices.Clone(c.path)
+}
+
+// Replace puts node in place of the one being visited. When done from Enter,
+// the children of the new node are visited instead. Replacing a node with nil
+// deletes it. It panics if node cannot be stored where the current one is.
+func (c *Cursor) Replace(node any) {
+	c.replacement = node
+	c.replaced = true
+}
+
+// Delete removes the node being visited from its parent. Nodes held in lists
+// are removed from them, others are set to nil.
+func (c *Cursor) Delete() {
+	c.Replace(nil)
+}
+
+// visitKeys lists, in document order, the fields of each node type that hold
+// child nodes. Fields that point to other parts of the tree, or to the schema,
+// once validated are not followed.
+var visitKeys = map[reflect.Type][]string{
+	reflect.TypeOf(&QueryDocument{}): {"Operations", "Fragments"},
+	reflect.TypeOf(&SchemaDocument{}): {
+		"Schema",
+		"SchemaExtension",
+		"Directives",
+		"Definitions",
+		"Extensions",
+	},
+
+	reflect.TypeOf(&OperationDefinition{}): {
+		"VariableDefinitions",
+		"Directives",
+		"SelectionSet",
+	},
+	reflect.TypeOf(&VariableDefinition{}): {"Type", "DefaultValue", "Directives"},
+	reflect.TypeOf(&FragmentDefinition{}): {"VariableDefinition", "Directives", "SelectionSet"},
+	reflect.TypeOf(&Field{}):              {"Arguments", "Directives", "SelectionSet"},
+	reflect.TypeOf(&FragmentSpread{}):     {"Directives"},
+	reflect.TypeOf(&InlineFragment{}):     {"Directives", "SelectionSet"},
+	reflect.TypeOf(&Argument{}):           {"Value"},
+	reflect.TypeOf(&Directive{}):          {"Arguments"},
+	reflect.TypeOf(&Value{}):              {"Children"},
+	reflect.TypeOf(&ChildValue{}):         {"Value"},
+	reflect.TypeOf(&Type{}):               {"Elem"},
+
+	reflect.TypeOf(&SchemaDefinition{}):    {"Directives", "OperationTypes"},
+	reflect.TypeOf(&DirectiveDefinition{}): {"Arguments"},
+	reflect.TypeOf(&Definition{}):          {"Directives", "Fields", "EnumValues"},
+	reflect.TypeOf(&FieldDefinition{}):     {"Arguments", "Type", "DefaultValue", "Directives"},
+	reflect.TypeOf(&ArgumentDefinition{}):  {"Type", "DefaultValue", "Directives"},
+	reflect.TypeOf(&EnumValueDefinition{}): {"Directives"},
+}
+
+// Visit walks the tree under root depth first, calling the callbacks of
+// visitor as it enters and leaves each node. root is usually a *QueryDocument
+// or a *SchemaDocument, but can be any node, e.g. a *Field or a *Value.
+//
+// Nodes are the pointers to the structs of this package that make up
+// documents: definitions, selections, arguments, directives, values and
+// types. Both the entries of object values and the items of list values are
+// held in *ChildValue nodes. Comments and positions are not visited.
+//
+// The tree is changed in place by the Cursor methods. Visit returns root, or
+// its replacement.
+func Visit(root any, visitor Visitor) any {
+	v := &visiting{visitor: visitor}
+	slot := reflect.ValueOf(&root).Elem()
+	if v.visit(slot) {
+		return nil
+	}
+	return root
+}
+
+type visiting struct {
+	visitor   Visitor
+	ancestors []any
+	path      Path
+	stopped   bool
+}
+
+// visit visits the node held by slot, which can be set. It reports whether the
+// node was deleted, leaving it to the caller to remove it from its parent.
+func (v *visiting) visit(slot reflect.Value) (deleted bool) {
+	if isNilNode(slot) {
+		return false
+	}
+	node := slot.Interface()
+
+	action := VisitContinue
+	if v.visitor.Enter != nil {
+		c := &Cursor{node: node, ancestors: v.ancestors, path: v.path}
+		action = v.visitor.Enter(c)
+		if c.replaced {
+			if c.replacement == nil {
+				v.stopped = action == VisitBreak
+				return true
+			}
+			node = replace(slot, node, c.replacement)
+		}
+	}
+	if action == VisitBreak {
+		v.stopped = true
+		return false
+	}
+
+	if action != VisitSkip {
+		v.visitChildren(node)
+		if v.stopped {
+			return false
+		}
+
+		if v.visitor.Leave != nil {
+			c := &Cursor{node: node, ancestors: v.ancestors, path: v.path}
+			action = v.visitor.Leave(c)
+			v.stopped = action == VisitBreak
+			if c.replaced {
+				if c.replacement == nil {
+					return true
+				}
+				replace(slot, node, c.replacement)
+			}
+		}
+	}
+	return false
+}
+
+func (v *visiting) visitChildren(node any) {
+	keys := visitKeys[reflect.TypeOf(node)]
+	if len(keys) == 0 {
+		return
+	}
+
+	v.ancestors = append(v.ancestors, node)
+	defer func() { v.ancestors = v.ancestors[:len(v.ancestors)-1] }()
+
+	value := reflect.ValueOf(node).Elem()
+	for _, key := range keys {
+		field := value.FieldByName(key)
+		v.path = append(v.path, PathName(key))
+		if field.Kind() == reflect.Slice {
+			for i := 0; i < field.Len() && !v.stopped; {
+				v.path = append(v.path, PathIndex(i))
+				deleted := v.visit(field.Index(i))
+				v.path = v.path[:len(v.path)-1]
+				if deleted {
+					field.Set(reflect.AppendSlice(field.Slice(0, i), field.Slice(i+1, field.Len())))
+					continue
+				}
+				i++
+			}
+		} else if v.visit(field) {
+			field.Set(reflect.Zero(field.Type()))
+		}
+		v.path = v.path[:len(v.path)-1]
+
+		if v.stopped {
+			return
+		}
+	}
+}
+
+// replace stores replacement in slot, in place of node.
+func replace(slot reflect.Value, node, replacement any) any {
+	value := reflect.ValueOf(replacement)
+	if !value.Type().AssignableTo(slot.Type()) {
+		panic(fmt.Errorf("ast.Visit: cannot replace %T with %T", node, replacement))
+	}
+	slot.Set(value)
+	return replacement
+}
+
+func isNilNode(slot reflect.Value) bool {
+	switch slot.Kind() {
+	case reflect.Interface:
+		return slot.IsNil() || isNilNode(slot.Elem())
+	case reflect.Pointer:
+		return slot.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package ast_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestVisit(t *testing.T) {
+	parseQuery := func(t *testing.T, query string) *QueryDocument {
+		t.Helper()
+		doc, err := parser.ParseQuery(&Source{Input: query})
+		require.NoError(t, err)
+		return doc
+	}
+	format := func(doc *QueryDocument) string {
+		var buf bytes.Buffer
+		formatter.NewFormatter(&buf, formatter.WithIndent(" ")).FormatQueryDocument(doc)
+		return buf.String()
+	}
+	name := func(node any) string {
+		switch node := node.(type) {
+		case *Field:
+			return "Field " + node.Name
+		case *Argument:
+			return "Argument " + node.Name
+		case *Value:
+			return "Value " + node.String()
+		case *ChildValue:
+			return "ChildValue " + node.Name
+		case *Type:
+			return "Type " + node.String()
+		}
+		return fmt.Sprintf("%T", node)
+	}
+
+	t.Run("enter and leave", func(t *testing.T) {
+		doc := parseQuery(t, `query ($ids: [ID!]) { a(ids: $ids, f: {x: [1]}) { b } }`)
+		var events []string
+		Visit(doc, Visitor{
+			Enter: func(c *Cursor) VisitAction {
+				events = append(events, "enter "+name(c.Node()))
+				return VisitContinue
+			},
+			Leave: func(c *Cursor) VisitAction {
+				events = append(events, "leave "+name(c.Node()))
+				return VisitContinue
+			},
+		})
+		require.Equal(t, []string{
+			"enter *ast.QueryDocument",
+			"enter *ast.OperationDefinition",
+			"enter *ast.VariableDefinition",
+			"enter Type [ID!]",
+			"enter Type ID!",
+			"leave Type ID!",
+			"leave Type [ID!]",
+			"leave *ast.VariableDefinition",
+			"enter Field a",
+			"enter Argument ids",
+			"enter Value $ids",
+			"leave Value $ids",
+			"leave Argument ids",
+			"enter Argument f",
+			"enter Value {x:[1]}",
+			"enter ChildValue x",
+			"enter Value [1]",
+			"enter ChildValue ",
+			"enter Value 1",
+			"leave Value 1",
+			"leave ChildValue ",
+			"leave Value [1]",
+			"leave ChildValue x",
+			"leave Value {x:[1]}",
+			"leave Argument f",
+			"enter Field b",
+			"leave Field b",
+			"leave Field a",
+			"leave *ast.OperationDefinition",
+			"leave *ast.QueryDocument",
+		}, events)
+	})
+
+	t.Run("skip and break", func(t *testing.T) {
+		doc := parseQuery(t, `{ a { b } c { d } e }`)
+		var entered []string
+		Visit(doc, Visitor{
+			Enter: func(c *Cursor) VisitAction {
+				field, ok := c.Node().(*Field)
+				if !ok {
+					return VisitContinue
+				}
+				entered = append(entered, field.Name)
+				switch field.Name {
+				case "a":
+					return VisitSkip
+				case "d":
+					return VisitBreak
+				}
+				return VisitContinue
+			},
+			Leave: func(c *Cursor) VisitAction {
+				if field, ok := c.Node().(*Field); ok {
+					require.NotEqual(t, "a", field.Name, "skipped nodes are not left")
+				}
+				return VisitContinue
+			},
+		})
+		require.Equal(t, []string{"a", "c", "d"}, entered)
+	})
+
+	t.Run("delete and replace", func(t *testing.T) {
+		doc := parseQuery(t, `{ a secret b(x: 1) { secret c } ... on Q @skip(if: true) { d } }`)
+		Visit(doc, Visitor{
+			Enter: func(c *Cursor) VisitAction {
+				switch node := c.Node().(type) {
+				case *Field:
+					if node.Name == "secret" {
+						c.Delete()
+					}
+				case *Value:
+					c.Replace(&Value{Kind: IntValue, Raw: "2"})
+				case *InlineFragment:
+					c.Replace(&Field{Alias: "e", Name: "e"})
+				}
+				return VisitContinue
+			},
+			Leave: func(c *Cursor) VisitAction {
+				if directive, ok := c.Node().(*Directive); ok && directive.Name == "skip" {
+					c.Delete()
+				}
+				return VisitContinue
+			},
+		})
+		require.Equal(t, "query {\n a\n b(x: 2) {\n  c\n }\n e\n}\n", format(doc))
+
+		require.Panics(t, func() {
+			Visit(doc, Visitor{Enter: func(c *Cursor) VisitAction {
+				if _, ok := c.Node().(*Value); ok {
+					c.Replace(&Field{})
+				}
+				return VisitContinue
+			}})
+		})
+	})
+
+	t.Run("replace root", func(t *testing.T) {
+		field := &Field{Name: "a"}
+		replaced := Visit(field, Visitor{Leave: func(c *Cursor) VisitAction {
+			c.Replace(&Field{Name: "b"})
+			return VisitContinue
+		}})
+		require.Equal(t, &Field{Name: "b"}, replaced)
+		require.Nil(t, Visit(field, Visitor{Enter: func(c *Cursor) VisitAction {
+			c.Delete()
+			return VisitContinue
+		}}))
+	})
+
+	t.Run("ancestors and path", func(t *testing.T) {
+		doc := parseQuery(t, `
+			query Q { a }
+			fragment F on T { b { ... on T { c @include(if: $v) } } }
+		`)
+		Visit(doc, Visitor{Enter: func(c *Cursor) VisitAction {
+			value, ok := c.Node().(*Value)
+			if !ok {
+				return VisitContinue
+			}
+			require.Equal(t, "$v", value.String())
+			require.Equal(t, "Fragments[0].SelectionSet[0].SelectionSet[0].SelectionSet[0]."+
+				"Directives[0].Arguments[0].Value", c.Path().String())
+
+			var ancestors []string
+			for _, ancestor := range c.Ancestors() {
+				ancestors = append(ancestors, name(ancestor))
+			}
+			require.Equal(t, []string{
+				"*ast.QueryDocument",
+				"*ast.FragmentDefinition",
+				"Field b",
+				"*ast.InlineFragment",
+				"Field c",
+				"*ast.Directive",
+				"Argument if",
+			}, ancestors)
+			require.Equal(t, "if", c.Parent().(*Argument).Name)
+			return VisitBreak
+		}})
+	})
+
+	t.Run("schema document", func(t *testing.T) {
+		doc, err := parser.ParseSchema(&Source{Input: `
+			schema { query: Query }
+			directive @tag(name: String!) on FIELD_DEFINITION
+			type Query { users(first: Int = 10): [User] @tag(name: "x") }
+			enum Role @tag(name: "y") { ADMIN }
+			extend type User { name: String }
+		`})
+		require.NoError(t, err)
+
+		var visited []string
+		Visit(doc, Visitor{Enter: func(c *Cursor) VisitAction {
+			switch node := c.Node().(type) {
+			case *SchemaDefinition, *OperationTypeDefinition:
+				visited = append(visited, fmt.Sprintf("%T", node))
+			case *DirectiveDefinition:
+				visited = append(visited, "DirectiveDefinition "+node.Name)
+			case *Definition:
+				visited = append(visited, "Definition "+node.Name)
+			case *FieldDefinition:
+				visited = append(visited, "FieldDefinition "+node.Name)
+			case *ArgumentDefinition:
+				visited = append(visited, "ArgumentDefinition "+node.Name)
+			case *EnumValueDefinition:
+				visited = append(visited, "EnumValueDefinition "+node.Name)
+			case *Directive:
+				visited = append(visited, "Directive "+node.Name)
+			case *Value:
+				visited = append(visited, "Value "+node.String())
+			}
+			return VisitContinue
+		}})
+		require.Equal(t, []string{
+			"*ast.SchemaDefinition",
+			"*ast.OperationTypeDefinition",
+			"DirectiveDefinition tag",
+			"ArgumentDefinition name",
+			"Definition Query",
+			"FieldDefinition users",
+			"ArgumentDefinition first",
+			"Value 10",
+			"Directive tag",
+			"Value \"x\"",
+			"Definition Role",
+			"Directive tag",
+			"Value \"y\"",
+			"EnumValueDefinition ADMIN",
+			"Definition User",
+			"FieldDefinition name",
+		}, visited)
+	})
+}
@@ -0,0 +1,437 @@
+// Package cost estimates what an operation costs to execute before it is run,
+// from the @cost and @listSize directives of the IBM GraphQL Cost Directive
+// specification.
+//
+// A field costs its weight, plus the weight of the arguments given to it,
+// plus the cost of its selection set. When it returns a list, its weight and
+// selection set are counted once for each item. Without @cost, composite types
+// weigh 1 and leaf types weigh 0. The size of a list is read from the slicing
+// arguments named by @listSize, or else its assumedSize. A field with
+// sizedFields applies that size to those of its subfields instead of itself,
+// as cursor connections need. Where fragments on an interface or union select
+// different fields, the most expensive possible type is counted.
+package cost
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Directives declares @cost and @listSize, for loading along with a schema
+// that uses them.
+var Directives = &ast.Source{
+	Name: "cost.graphql",
+	Input: `directive @cost(weight: String!) on
+	| ARGUMENT_DEFINITION
+	| ENUM
+	| FIELD_DEFINITION
+	| INPUT_FIELD_DEFINITION
+	| OBJECT
+	| SCALAR
+directive @listSize(
+	assumedSize: Int
+	slicingArguments: [String!]
+	sizedFields: [String!]
+	requireOneSlicingArgument: Boolean = true
+) on FIELD_DEFINITION
+`,
+}
+
+// Option configures how cost is estimated.
+type Option func(*analyzer)
+
+// WithDefaultListSize sets the size of lists that @listSize does not give a
+// size for, which is 1 by default.
+func WithDefaultListSize(size int) Option {
+	return func(a *analyzer) {
+		a.defaultListSize = float64(size)
+	}
+}
+
+// Result is the estimated cost of an operation.
+type Result struct {
+	Cost float64
+	// Fields has the cost of each field, including what is selected under
+	// it, in the order the fields are selected.
+	Fields []*FieldCost
+}
+
+// FieldCost is the cost of the field at Path, which is made of response
+// keys only: the cost of a list field covers all of its items.
+type FieldCost struct {
+	Path ast.Path
+	Cost float64
+}
+
+// Analyze estimates the cost of operation, which must come from a document
+// validated against schema. The variables are used to evaluate slicing
+// arguments and @skip and @include, and may be nil to rely on the defaults of
+// the variable definitions. A slicing argument given by a variable with
+// neither a value nor a default could be any size, so the list takes its
+// assumedSize instead. An operation whose fragments spread each other has no
+// cost, as it cannot be run; NoFragmentCyclesRule reports it.
+func Analyze(
+	schema *ast.Schema,
+	operation *ast.OperationDefinition,
+	variables map[string]any,
+	options ...Option,
+) *Result {
+	a := &analyzer{schema: schema, variables: variables, defaultListSize: 1}
+	for _, option := range options {
+		option(a)
+	}
+
+	var root *ast.Definition
+	switch operation.Operation {
+	case ast.Mutation:
+		root = schema.Mutation
+	case ast.Subscription:
+		root = schema.Subscription
+	default:
+		root = schema.Query
+	}
+	if root == nil || spreadsCycle(operation.SelectionSet, map[string]bool{}, map[string]bool{}) {
+		return &Result{}
+	}
+
+	c, fields := a.objectCost(operation.SelectionSet, root, nil, nil)
+	return &Result{Cost: c, Fields: fields}
+}
+
+type analyzer struct {
+	schema          *ast.Schema
+	variables       map[string]any
+	defaultListSize float64
+}
+
+// selectionSetCost is the cost of selectionSet on a value of type def, which
+// for an abstract type is that of its most expensive possible type.
+func (a *analyzer) selectionSetCost(
+	selectionSet ast.SelectionSet,
+	def *ast.Definition,
+	path ast.Path,
+	sizes map[string]float64,
+) (float64, []*FieldCost) {
+	if !def.IsAbstractType() {
+		return a.objectCost(selectionSet, def, path, sizes)
+	}
+
+	var maxCost float64
+	var maxFields []*FieldCost
+	for _, possible := range a.schema.GetPossibleTypes(def) {
+		if possible.Kind != ast.Object {
+			continue
+		}
+		c, fields := a.objectCost(selectionSet, possible, path, sizes)
+		if maxFields == nil || c > maxCost {
+			maxCost, maxFields = c, fields
+		}
+	}
+	return maxCost, maxFields
+}
+
+// objectCost is the cost of the fields of selectionSet that apply to the
+// object type def. sizes has the list sizes that the parent field gives to
+// its sizedFields.
+func (a *analyzer) objectCost(
+	selectionSet ast.SelectionSet,
+	def *ast.Definition,
+	path ast.Path,
+	sizes map[string]float64,
+) (float64, []*FieldCost) {
+	var total float64
+	var breakdown []*FieldCost
+	for _, f := range ast.CollectFields(a.schema, selectionSet, def, a.variables) {
+		field := f.Fields[0]
+		fieldDef := def.Fields.ForName(field.Name)
+		if fieldDef == nil {
+			continue
+		}
+
+		fieldPath := append(append(ast.Path{}, path...), ast.PathName(f.ResponseKey))
+		fieldCost := &FieldCost{Path: fieldPath}
+		breakdown = append(breakdown, fieldCost)
+
+		typeDef := a.schema.Types[fieldDef.Type.Name()]
+		weight := fieldWeight(fieldDef, typeDef)
+		argsCost := a.argumentsCost(field, fieldDef)
+
+		size, ok := sizes[field.Name]
+		listSize := fieldDef.Directives.ForName("listSize")
+		var childSizes map[string]float64
+		switch {
+		case listSize != nil && listSize.Arguments.ForName("sizedFields") != nil:
+			childSize := a.listSize(field, listSize)
+			childSizes = map[string]float64{}
+			for _, name := range stringList(listSize, "sizedFields") {
+				childSizes[name] = childSize
+			}
+			if !ok {
+				size = 1
+			}
+		case !ok && fieldDef.Type.Elem != nil:
+			size = a.listSize(field, listSize)
+		case !ok:
+			size = 1
+		}
+
+		var childCost float64
+		if typeDef != nil && typeDef.IsCompositeType() {
+			var children []*FieldCost
+			childCost, children = a.selectionSetCost(
+				f.SelectionSet(),
+				typeDef,
+				fieldPath,
+				childSizes,
+			)
+			breakdown = append(breakdown, children...)
+		}
+
+		fieldCost.Cost = argsCost + size*(weight+childCost)
+		total += fieldCost.Cost
+	}
+	return total, breakdown
+}
+
+// fieldWeight is the @cost of fieldDef, or else of its type.
+func fieldWeight(fieldDef *ast.FieldDefinition, typeDef *ast.Definition) float64 {
+	if w, ok := weight(fieldDef.Directives); ok {
+		return w
+	}
+	if typeDef == nil {
+		return 0
+	}
+	if w, ok := weight(typeDef.Directives); ok {
+		return w
+	}
+	if typeDef.IsCompositeType() {
+		return 1
+	}
+	return 0
+}
+
+// argumentsCost is the @cost of the arguments given to field, and of the
+// input fields given within them.
+func (a *analyzer) argumentsCost(field *ast.Field, fieldDef *ast.FieldDefinition) float64 {
+	var total float64
+	for _, arg := range field.Arguments {
+		argDef := fieldDef.Arguments.ForName(arg.Name)
+		if argDef == nil {
+			continue
+		}
+		if w, ok := weight(argDef.Directives); ok {
+			total += w
+		}
+		total += a.inputCost(argDef.Type, arg.Value)
+	}
+	return total
+}
+
+func (a *analyzer) inputCost(typ *ast.Type, value *ast.Value) float64 {
+	if value == nil {
+		return 0
+	}
+	if value.Kind == ast.Variable {
+		v, _ := value.Value(a.variables)
+		return a.variableCost(typ, v)
+	}
+
+	var total float64
+	switch {
+	case typ.Elem != nil && value.Kind == ast.ListValue:
+		for _, child := range value.Children {
+			total += a.inputCost(typ.Elem, child.Value)
+		}
+	case value.Kind == ast.ObjectValue:
+		def := a.schema.Types[typ.Name()]
+		if def == nil {
+			return 0
+		}
+		for _, child := range value.Children {
+			fieldDef := def.Fields.ForName(child.Name)
+			if fieldDef == nil {
+				continue
+			}
+			if w, ok := weight(fieldDef.Directives); ok {
+				total += w
+			}
+			total += a.inputCost(fieldDef.Type, child.Value)
+		}
+	}
+	return total
+}
+
+// variableCost is inputCost for the value of a variable.
+func (a *analyzer) variableCost(typ *ast.Type, value any) float64 {
+	var total float64
+	switch value := value.(type) {
+	case []any:
+		if typ.Elem == nil {
+			return 0
+		}
+		for _, item := range value {
+			total += a.variableCost(typ.Elem, item)
+		}
+	case map[string]any:
+		def := a.schema.Types[typ.Name()]
+		if def == nil {
+			return 0
+		}
+		for _, fieldDef := range def.Fields {
+			item, ok := value[fieldDef.Name]
+			if !ok {
+				continue
+			}
+			if w, ok := weight(fieldDef.Directives); ok {
+				total += w
+			}
+			total += a.variableCost(fieldDef.Type, item)
+		}
+	}
+	return total
+}
+
+// listSize is the largest of the slicing arguments given to field, or else
+// the assumedSize of listSize.
+func (a *analyzer) listSize(field *ast.Field, listSize *ast.Directive) float64 {
+	if listSize == nil {
+		return a.defaultListSize
+	}
+
+	found := false
+	var size float64
+	for _, name := range stringList(listSize, "slicingArguments") {
+		v, known := a.argumentValue(field, name)
+		if !known {
+			found = false
+			break
+		}
+		if n, ok := number(v); ok && (!found || n > size) {
+			found = true
+			size = n
+		}
+	}
+	if found {
+		return size
+	}
+
+	if arg := listSize.Arguments.ForName("assumedSize"); arg != nil {
+		v, _ := arg.Value.Value(nil)
+		if n, ok := number(v); ok {
+			return n
+		}
+	}
+	return a.defaultListSize
+}
+
+// argumentValue is the value of the argument name of field, falling back on
+// the defaults of the variable it is given by and of its definition. It
+// reports false when the argument is given by a variable that has neither a
+// value nor a default.
+func (a *analyzer) argumentValue(field *ast.Field, name string) (any, bool) {
+	if arg := field.Arguments.ForName(name); arg != nil {
+		if arg.Value.Kind == ast.Variable {
+			_, ok := a.variables[arg.Value.Raw]
+			def := arg.Value.VariableDefinition
+			if !ok && (def == nil || def.DefaultValue == nil) {
+				return nil, false
+			}
+		}
+		v, _ := arg.Value.Value(a.variables)
+		return v, true
+	}
+	if field.Definition == nil {
+		return nil, true
+	}
+	if argDef := field.Definition.Arguments.ForName(name); argDef != nil {
+		v, _ := argDef.DefaultValue.Value(nil)
+		return v, true
+	}
+	return nil, true
+}
+
+// spreadsCycle reports whether selectionSet spreads a fragment that is
+// already open, being one of those spread on the way to it. done has the
+// fragments already searched.
+func spreadsCycle(selectionSet ast.SelectionSet, open, done map[string]bool) bool {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if spreadsCycle(selection.SelectionSet, open, done) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if spreadsCycle(selection.SelectionSet, open, done) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			fragment := selection.Definition
+			if fragment == nil || done[fragment.Name] {
+				continue
+			}
+			if open[fragment.Name] {
+				return true
+			}
+			open[fragment.Name] = true
+			if spreadsCycle(fragment.SelectionSet, open, done) {
+				return true
+			}
+			delete(open, fragment.Name)
+			done[fragment.Name] = true
+		}
+	}
+	return false
+}
+
+// weight reads the weight of @cost in directives. The specification makes it
+// a String so that it can be fractional, but numbers are accepted too.
+func weight(directives ast.DirectiveList) (float64, bool) {
+	c := directives.ForName("cost")
+	if c == nil {
+		return 0, false
+	}
+	arg := c.Arguments.ForName("weight")
+	if arg == nil || arg.Value == nil {
+		return 0, false
+	}
+	switch arg.Value.Kind {
+	case ast.StringValue, ast.BlockValue, ast.IntValue, ast.FloatValue:
+		w, err := strconv.ParseFloat(arg.Value.Raw, 64)
+		return w, err == nil
+	}
+	return 0, false
+}
+
+func stringList(directive *ast.Directive, name string) []string {
+	arg := directive.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil {
+		return nil
+	}
+	if arg.Value.Kind == ast.StringValue {
+		return []string{arg.Value.Raw}
+	}
+	var list []string
+	for _, child := range arg.Value.Children {
+		list = append(list, child.Value.Raw)
+	}
+	return list
+}
+
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
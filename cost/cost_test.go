@@ -0,0 +1,165 @@
+package cost_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/cost"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+const schemaSDL = `
+type Query {
+	users(first: Int, last: Int): [User!]!
+		@listSize(slicingArguments: ["first", "last"], assumedSize: 50)
+	search(term: String! @cost(weight: "3")): [SearchResult] @listSize(assumedSize: 10)
+	connection(first: Int = 5): UserConnection
+		@listSize(slicingArguments: ["first"], sizedFields: ["edges"])
+	filter(input: Filter): Int
+	expensive: Int @cost(weight: "2.5")
+	tags: [String]
+}
+
+type User @cost(weight: "2") {
+	name: String
+	posts: [Post] @listSize(assumedSize: 3)
+	friends: [User]
+}
+
+type Post {
+	title: String
+}
+
+union SearchResult = User | Post
+
+type UserConnection {
+	edges: [UserEdge]
+	total: Int
+}
+
+type UserEdge {
+	node: User
+}
+
+input Filter {
+	name: String @cost(weight: "4")
+}
+`
+
+func TestAnalyze(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(cost.Directives, &ast.Source{Input: schemaSDL})
+	analyze := func(query string, variables map[string]any, options ...cost.Option) *cost.Result {
+		doc, errs := gqlparser.LoadQueryWithRules(schema, query, nil)
+		require.Empty(t, errs)
+		return cost.Analyze(schema, doc.Operations[0], variables, options...)
+	}
+	path := func(keys ...string) ast.Path {
+		var p ast.Path
+		for _, key := range keys {
+			p = append(p, ast.PathName(key))
+		}
+		return p
+	}
+
+	t.Run("weights and list sizes", func(t *testing.T) {
+		result := analyze(`{ users(first: 2) { name posts { title } } expensive tags }`, nil)
+		require.InDelta(t, 12.5, result.Cost, 0)
+		require.Equal(t, []*cost.FieldCost{
+			{Path: path("users"), Cost: 10},
+			{Path: path("users", "name"), Cost: 0},
+			{Path: path("users", "posts"), Cost: 3},
+			{Path: path("users", "posts", "title"), Cost: 0},
+			{Path: path("expensive"), Cost: 2.5},
+			{Path: path("tags"), Cost: 0},
+		}, result.Fields)
+	})
+
+	t.Run("slicing arguments from variables", func(t *testing.T) {
+		query := `query ($n: Int = 4) { users(first: $n, last: 1) { name } }`
+		require.InDelta(t, 8, analyze(query, nil).Cost, 0)
+		require.InDelta(t, 20, analyze(query, map[string]any{"n": 10}).Cost, 0)
+		require.InDelta(t, 100, analyze(`{ users { name } }`, nil).Cost, 0)
+		unknown := `query ($n: Int) { users(first: $n, last: 1) { name } }`
+		require.InDelta(t, 100, analyze(unknown, nil).Cost, 0)
+		require.InDelta(t, 20, analyze(unknown, map[string]any{"n": 10}).Cost, 0)
+	})
+
+	t.Run("most expensive possible type", func(t *testing.T) {
+		result := analyze(`{
+			search(term: "a") {
+				... on User { name posts { title } }
+				... on Post { title }
+			}
+		}`, nil)
+		require.InDelta(t, 43, result.Cost, 0)
+		require.Equal(t, path("search", "posts"), result.Fields[2].Path)
+	})
+
+	t.Run("sized fields", func(t *testing.T) {
+		result := analyze(`{ connection { edges { node { name } } total } }`, nil)
+		require.InDelta(t, 16, result.Cost, 0)
+		edges := &cost.FieldCost{Path: path("connection", "edges"), Cost: 15}
+		require.Equal(t, edges, result.Fields[1])
+	})
+
+	t.Run("input field weights", func(t *testing.T) {
+		require.InDelta(t, 4, analyze(`{ filter(input: {name: "x"}) }`, nil).Cost, 0)
+		query := `query ($f: Filter) { filter(input: $f) }`
+		require.InDelta(t, 0, analyze(query, nil).Cost, 0)
+		variables := map[string]any{"f": map[string]any{"name": "x"}}
+		require.InDelta(t, 4, analyze(query, variables).Cost, 0)
+	})
+
+	t.Run("default list size", func(t *testing.T) {
+		query := `{ users(first: 1) { friends { name } } }`
+		require.InDelta(t, 4, analyze(query, nil).Cost, 0)
+		require.InDelta(t, 42, analyze(query, nil, cost.WithDefaultListSize(20)).Cost, 0)
+	})
+
+	t.Run("skip and include", func(t *testing.T) {
+		query := `query ($skip: Boolean!) { expensive @skip(if: $skip) }`
+		require.InDelta(t, 0, analyze(query, map[string]any{"skip": true}).Cost, 0)
+		require.InDelta(t, 2.5, analyze(query, map[string]any{"skip": false}).Cost, 0)
+	})
+}
+
+func TestMaxCostRule(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(cost.Directives, &ast.Source{Input: schemaSDL})
+	rs := rules.NewRules(cost.MaxCostRule(10))
+
+	_, errs := gqlparser.LoadQueryWithRules(schema, `{ users(first: 2) { name } }`, rs)
+	require.Empty(t, errs)
+
+	_, errs = gqlparser.LoadQueryWithRules(schema, `query Big { users { name } }`, rs)
+	require.Equal(t, gqlerror.List{{
+		Message:   `Operation "Big" has a cost of 100, which exceeds the maximum cost of 10.`,
+		Locations: []gqlerror.Location{{Line: 1, Column: 1}},
+		Rule:      "MaxCost",
+	}}, errs)
+
+	_, errs = gqlparser.LoadQueryWithRules(
+		schema,
+		`query Unknown($n: Int) { users(first: $n, last: 1) { name } }`,
+		rs,
+	)
+	require.Len(t, errs, 1)
+	require.Equal(
+		t,
+		`Operation "Unknown" has a cost of 100, which exceeds the maximum cost of 10.`,
+		errs[0].Message,
+	)
+
+	for _, query := range []string{
+		`{ users { ...A } } fragment A on User { ...B } fragment B on User { ...A }`,
+		`{ users { ...A } }
+		fragment A on User { friends { ...B } }
+		fragment B on User { friends { ...A } }`,
+	} {
+		_, errs = gqlparser.LoadQueryWithRules(schema, query, rs)
+		require.Empty(t, errs)
+	}
+}
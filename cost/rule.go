@@ -0,0 +1,41 @@
+package cost
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxCostRule rejects operations estimated to cost more than budget. It runs
+// during validation, before variables are known, so slicing arguments given
+// by variables take the defaults of the variable definitions, and lists whose
+// slicing variables have no default take their assumedSize. Call Analyze with
+// the variables of a request for a closer estimate.
+func MaxCostRule(budget float64, options ...Option) core.Rule {
+	return core.Rule{
+		Name: "MaxCost",
+		RuleFunc: func(observers *core.Events, addError core.AddErrFunc) {
+			observers.OnOperation(func(walker *core.Walker, operation *ast.OperationDefinition) {
+				result := Analyze(walker.Schema, operation, nil, options...)
+				if result.Cost <= budget {
+					return
+				}
+
+				name := "Anonymous operation"
+				if operation.Name != "" {
+					name = "Operation " + strconv.Quote(operation.Name)
+				}
+				addError(
+					core.Message(
+						`%s has a cost of %g, which exceeds the maximum cost of %g.`,
+						name,
+						result.Cost,
+						budget,
+					),
+					core.At(operation.Position),
+				)
+			})
+		},
+	}
+}
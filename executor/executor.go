@@ -0,0 +1,615 @@
+// Package executor executes operations against a schema, following the
+// ExecuteRequest and ExecuteSelectionSet algorithms of the spec, with the
+// value of each field coming from a resolver registered for it.
+package executor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/introspection"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// ResolverFunc returns the value of a field. An error makes the field null
+// and is reported at its path.
+type ResolverFunc func(ctx context.Context, params ResolveParams) (any, error)
+
+// TypeResolverFunc returns the name of the object type of value, which was
+// returned for a field of the interface or union abstractType.
+type TypeResolverFunc func(
+	ctx context.Context,
+	value any,
+	abstractType *ast.Definition,
+) (string, error)
+
+// ResolveParams is everything known about a field as it is resolved.
+type ResolveParams struct {
+	// Source is the value of the parent object.
+	Source any
+	// Args are the argument values of the field, with defaults applied.
+	Args map[string]any
+	// Field is the first of Fields, which are every field selected under the
+	// same response key.
+	Field           *ast.Field
+	Fields          []*ast.Field
+	FieldDefinition *ast.FieldDefinition
+	ParentType      *ast.Definition
+	Path            ast.Path
+
+	Schema    *ast.Schema
+	Document  *ast.QueryDocument
+	Operation *ast.OperationDefinition
+	Variables map[string]any
+}
+
+type Executor struct {
+	schema       *ast.Schema
+	resolvers    map[string]ResolverFunc
+	typeResolver TypeResolverFunc
+	concurrency  int
+}
+
+// NewExecutor returns an Executor for schema that resolves every field with
+// DefaultResolver and abstract types with DefaultTypeResolver until told
+// otherwise.
+func NewExecutor(schema *ast.Schema) *Executor {
+	return &Executor{
+		schema:       schema,
+		resolvers:    map[string]ResolverFunc{},
+		typeResolver: DefaultTypeResolver,
+	}
+}
+
+// AddResolver sets the resolver of the field at coordinate, such as
+// "Query.user". Fields of interfaces are resolved by the resolvers of the
+// object types implementing them.
+func (e *Executor) AddResolver(coordinate string, resolver ResolverFunc) {
+	e.resolvers[coordinate] = resolver
+}
+
+// SetTypeResolver sets how the object type of a value returned for an
+// interface or union is found.
+func (e *Executor) SetTypeResolver(resolver TypeResolverFunc) {
+	e.typeResolver = resolver
+}
+
+// SetConcurrency sets how many goroutines each execution may start to resolve
+// fields in parallel. It is 0 until set, so that fields are resolved one after
+// another on the goroutine calling Execute. Once every goroutine is busy, the
+// remaining fields are resolved on the goroutine that selected them.
+func (e *Executor) SetConcurrency(n int) {
+	e.concurrency = n
+}
+
+type Request struct {
+	// Document must have been validated against the schema of the Executor.
+	Document      *ast.QueryDocument
+	OperationName string
+	Variables     map[string]any
+	// RootValue is the Source of the fields of the root type.
+	RootValue any
+}
+
+type Response struct {
+	// Data has its fields in the order they were selected.
+	Data   ast.ResultMap `json:"data"`
+	Errors gqlerror.List `json:"errors,omitempty"`
+}
+
+// Execute runs the operation of req. Errors with the request itself, such as
+// invalid variables, leave Data nil. Field errors are ordered by path.
+//
+// Fields are resolved one after another unless SetConcurrency allows more
+// goroutines, in which case every selection set other than the root fields
+// of a mutation has its fields resolved in parallel, up to that limit.
+func (e *Executor) Execute(ctx context.Context, req *Request) *Response {
+	op, gqlErr := getOperation(req.Document, req.OperationName)
+	if gqlErr != nil {
+		return &Response{Errors: gqlerror.List{gqlErr}}
+	}
+
+	variables, err := validator.VariableValues(e.schema, op, req.Variables)
+	if err != nil {
+		return &Response{Errors: gqlerror.List{gqlerror.WrapIfUnwrapped(err)}}
+	}
+
+	var root *ast.Definition
+	switch op.Operation {
+	case ast.Mutation:
+		root = e.schema.Mutation
+	case ast.Subscription:
+		return &Response{Errors: gqlerror.List{gqlerror.ErrorPosf(
+			op.Position,
+			"Subscription operations are not supported.",
+		)}}
+	default:
+		root = e.schema.Query
+	}
+	if root == nil {
+		return &Response{Errors: gqlerror.List{gqlerror.ErrorPosf(
+			op.Position,
+			"Schema is not configured to execute %s operation.",
+			op.Operation,
+		)}}
+	}
+
+	ex := &execution{
+		executor:  e,
+		document:  req.Document,
+		operation: op,
+		variables: variables,
+	}
+	if e.concurrency > 0 {
+		ex.workers = make(chan struct{}, e.concurrency)
+	}
+	data, _ := ex.executeSelectionSet(
+		ctx,
+		op.SelectionSet,
+		root,
+		req.RootValue,
+		nil,
+		op.Operation == ast.Mutation,
+	)
+
+	sort.SliceStable(ex.errors, func(i, j int) bool {
+		return comparePaths(ex.errors[i].Path, ex.errors[j].Path) < 0
+	})
+	return &Response{Data: data, Errors: ex.errors}
+}
+
+func getOperation(
+	doc *ast.QueryDocument,
+	operationName string,
+) (*ast.OperationDefinition, *gqlerror.Error) {
+	if operationName == "" {
+		switch len(doc.Operations) {
+		case 0:
+			return nil, gqlerror.Errorf("Must provide an operation.")
+		case 1:
+			return doc.Operations[0], nil
+		default:
+			return nil, gqlerror.Errorf(
+				"Must provide operation name if query contains multiple operations.",
+			)
+		}
+	}
+	if op := doc.Operations.ForName(operationName); op != nil {
+		return op, nil
+	}
+	return nil, gqlerror.Errorf(`Unknown operation named "%s".`, operationName)
+}
+
+// execution is the state of a single request.
+type execution struct {
+	executor  *Executor
+	document  *ast.QueryDocument
+	operation *ast.OperationDefinition
+	variables map[string]any
+	// workers has a slot for each goroutine that may be resolving fields, and
+	// is nil when fields are resolved serially.
+	workers chan struct{}
+
+	mu     sync.Mutex
+	errors gqlerror.List
+}
+
+// The values returned while executing are paired with an ok flag, which is
+// false when the value is null because of an error that has been reported.
+// That null propagates up to the closest position that is nullable.
+
+func (ex *execution) executeSelectionSet(
+	ctx context.Context,
+	selectionSet ast.SelectionSet,
+	objectType *ast.Definition,
+	source any,
+	path ast.Path,
+	serial bool,
+) (ast.ResultMap, bool) {
+	fields := ast.CollectFields(ex.executor.schema, selectionSet, objectType, ex.variables)
+	values := make([]any, len(fields))
+	oks := make([]bool, len(fields))
+
+	// The last field is resolved on this goroutine, which would otherwise
+	// only be waiting.
+	var wg sync.WaitGroup
+	for i, f := range fields {
+		if !serial && i < len(fields)-1 && ex.startWorker() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer ex.stopWorker()
+				values[i], oks[i] = ex.executeField(ctx, objectType, source, f, path)
+			}()
+			continue
+		}
+		values[i], oks[i] = ex.executeField(ctx, objectType, source, f, path)
+	}
+	wg.Wait()
+
+	result := make(ast.ResultMap, len(fields))
+	for i, f := range fields {
+		if !oks[i] {
+			return nil, false
+		}
+		result[i] = ast.ResultField{Key: f.ResponseKey, Value: values[i]}
+	}
+	return result, true
+}
+
+// startWorker takes a slot for a new goroutine if one is free. It never
+// waits, as the goroutines holding the slots may themselves be waiting for
+// the fields they selected.
+func (ex *execution) startWorker() bool {
+	select {
+	case ex.workers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ex *execution) stopWorker() {
+	<-ex.workers
+}
+
+func (ex *execution) executeField(
+	ctx context.Context,
+	objectType *ast.Definition,
+	source any,
+	f *ast.CollectedField,
+	parentPath ast.Path,
+) (any, bool) {
+	schema := ex.executor.schema
+	field := f.Fields[0]
+	path := appendPath(parentPath, ast.PathName(f.ResponseKey))
+
+	switch field.Name {
+	case "__typename":
+		return objectType.Name, true
+	case "__schema", "__type":
+		if objectType == schema.Query {
+			return introspection.ResolveField(schema, f, ex.variables), true
+		}
+	}
+
+	def := objectType.Fields.ForName(field.Name)
+	if def == nil {
+		// Validation rules out fields that do not exist, so this can only be
+		// a document that was not validated against this schema.
+		ex.addError(gqlerror.ErrorPathf(
+			path,
+			`Cannot query field "%s" on type "%s".`,
+			field.Name,
+			objectType.Name,
+		), field)
+		return nil, true
+	}
+
+	resolver := ex.executor.resolvers[objectType.Name+"."+field.Name]
+	if resolver == nil {
+		resolver = DefaultResolver
+	}
+	args, err := argumentValues(field, ex.variables)
+	if err != nil {
+		ex.addFieldError(err, field, path)
+		return nil, !def.Type.NonNull
+	}
+	params := ResolveParams{
+		Source:          source,
+		Args:            args,
+		Field:           field,
+		Fields:          f.Fields,
+		FieldDefinition: def,
+		ParentType:      objectType,
+		Path:            path,
+		Schema:          schema,
+		Document:        ex.document,
+		Operation:       ex.operation,
+		Variables:       ex.variables,
+	}
+
+	value, err := callResolver(ctx, resolver, params)
+	if err != nil {
+		ex.addFieldError(err, field, path)
+		return nil, !def.Type.NonNull
+	}
+
+	c := completion{field: field, collected: f, parentType: objectType}
+	completed, ok := ex.completeValue(ctx, c, def.Type, value, path)
+	if !ok && !def.Type.NonNull {
+		return nil, true
+	}
+	return completed, ok
+}
+
+// argumentValues returns the arguments of field with defaults applied. It
+// fails on literals that have no Go value, such as an Int beyond int64.
+func argumentValues(
+	field *ast.Field,
+	variables map[string]any,
+) (args map[string]any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid argument value: %v", r)
+		}
+	}()
+	if args = field.ArgumentMap(variables); args == nil {
+		args = map[string]any{}
+	}
+	return args, nil
+}
+
+func callResolver(
+	ctx context.Context,
+	resolver ResolverFunc,
+	params ResolveParams,
+) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in resolver: %v", r)
+		}
+	}()
+	return resolver(ctx, params)
+}
+
+func callTypeResolver(
+	ctx context.Context,
+	resolver TypeResolverFunc,
+	value any,
+	abstractType *ast.Definition,
+) (name string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in type resolver: %v", r)
+		}
+	}()
+	return resolver(ctx, value, abstractType)
+}
+
+// completion is the field whose value is being completed.
+type completion struct {
+	field      *ast.Field
+	collected  *ast.CollectedField
+	parentType *ast.Definition
+}
+
+func (ex *execution) completeValue(
+	ctx context.Context,
+	c completion,
+	typ *ast.Type,
+	value any,
+	path ast.Path,
+) (any, bool) {
+	if typ.NonNull {
+		completed, ok := ex.completeValue(ctx, c, nullable(typ), value, path)
+		if !ok {
+			return nil, false
+		}
+		if completed == nil {
+			ex.addError(gqlerror.ErrorPathf(
+				path,
+				"Cannot return null for non-nullable field %s.%s.",
+				c.parentType.Name,
+				c.field.Name,
+			), c.field)
+			return nil, false
+		}
+		return completed, true
+	}
+
+	if isNil(value) {
+		return nil, true
+	}
+
+	if typ.Elem != nil {
+		return ex.completeList(ctx, c, typ.Elem, value, path)
+	}
+
+	def := ex.executor.schema.Types[typ.NamedType]
+	if def == nil {
+		ex.addError(gqlerror.ErrorPathf(path, "Unknown type %s.", typ.NamedType), c.field)
+		return nil, false
+	}
+
+	switch def.Kind {
+	case ast.Scalar, ast.Enum:
+		serialized, err := serializeLeaf(def, value)
+		if err != nil {
+			ex.addFieldError(err, c.field, path)
+			return nil, false
+		}
+		return serialized, true
+	case ast.Interface, ast.Union:
+		objectType, err := ex.resolveAbstractType(ctx, c, def, value)
+		if err != nil {
+			ex.addFieldError(err, c.field, path)
+			return nil, false
+		}
+		return ex.completeObject(ctx, c, objectType, value, path)
+	default:
+		return ex.completeObject(ctx, c, def, value, path)
+	}
+}
+
+func (ex *execution) completeList(
+	ctx context.Context,
+	c completion,
+	itemType *ast.Type,
+	value any,
+	path ast.Path,
+) (any, bool) {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		ex.addError(gqlerror.ErrorPathf(
+			path,
+			`Expected Iterable, but did not find one for field "%s.%s".`,
+			c.parentType.Name,
+			c.field.Name,
+		), c.field)
+		return nil, false
+	}
+
+	result := make([]any, list.Len())
+	for i := range result {
+		item, ok := ex.completeValue(
+			ctx,
+			c,
+			itemType,
+			list.Index(i).Interface(),
+			appendPath(path, ast.PathIndex(i)),
+		)
+		if !ok {
+			if itemType.NonNull {
+				return nil, false
+			}
+			item = nil
+		}
+		result[i] = item
+	}
+	return result, true
+}
+
+func (ex *execution) completeObject(
+	ctx context.Context,
+	c completion,
+	objectType *ast.Definition,
+	value any,
+	path ast.Path,
+) (any, bool) {
+	data, ok := ex.executeSelectionSet(
+		ctx,
+		c.collected.SelectionSet(),
+		objectType,
+		value,
+		path,
+		false,
+	)
+	if !ok {
+		return nil, false
+	}
+	return data, true
+}
+
+func (ex *execution) resolveAbstractType(
+	ctx context.Context,
+	c completion,
+	abstractType *ast.Definition,
+	value any,
+) (*ast.Definition, error) {
+	schema := ex.executor.schema
+	name, err := callTypeResolver(ctx, ex.executor.typeResolver, value, abstractType)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, gqlerror.Errorf(
+			`Abstract type "%s" must resolve to an Object type at runtime for field "%s.%s".`,
+			abstractType.Name,
+			c.parentType.Name,
+			c.field.Name,
+		)
+	}
+
+	objectType := schema.Types[name]
+	if objectType == nil || objectType.Kind != ast.Object {
+		return nil, gqlerror.Errorf(
+			`Abstract type "%s" was resolved to a type "%s" that does not exist inside the schema.`,
+			abstractType.Name,
+			name,
+		)
+	}
+	for _, possible := range schema.GetPossibleTypes(abstractType) {
+		if possible == objectType {
+			return objectType, nil
+		}
+	}
+	return nil, gqlerror.Errorf(
+		`Runtime Object type "%s" is not a possible type for "%s".`,
+		name,
+		abstractType.Name,
+	)
+}
+
+func (ex *execution) addError(err *gqlerror.Error, field *ast.Field) {
+	if len(err.Locations) == 0 && field.Position != nil {
+		err.Locations = []gqlerror.Location{
+			{Line: field.Position.Line, Column: field.Position.Column},
+		}
+	}
+	ex.mu.Lock()
+	ex.errors = append(ex.errors, err)
+	ex.mu.Unlock()
+}
+
+// addFieldError reports an error returned while resolving the field at path.
+// A *gqlerror.Error keeps its message and extensions, but is copied rather
+// than changed, as a resolver may return the same one more than once.
+func (ex *execution) addFieldError(err error, field *ast.Field, path ast.Path) {
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		copied := *gqlErr
+		gqlErr = &copied
+	} else {
+		gqlErr = gqlerror.Wrap(err)
+	}
+	if gqlErr.Path == nil {
+		gqlErr.Path = path
+	}
+	ex.addError(gqlErr, field)
+}
+
+// comparePaths orders paths element by element, with list indexes compared as
+// numbers rather than as text, and a path before the paths below it.
+func comparePaths(a, b ast.Path) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch x := a[i].(type) {
+		case ast.PathIndex:
+			y, ok := b[i].(ast.PathIndex)
+			if !ok {
+				return -1
+			}
+			if x != y {
+				return cmp.Compare(x, y)
+			}
+		case ast.PathName:
+			y, ok := b[i].(ast.PathName)
+			if !ok {
+				return 1
+			}
+			if x != y {
+				return cmp.Compare(x, y)
+			}
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func appendPath(path ast.Path, elem ast.PathElement) ast.Path {
+	result := make(ast.Path, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, elem)
+}
+
+func nullable(t *ast.Type) *ast.Type {
+	return &ast.Type{NamedType: t.NamedType, Elem: t.Elem, Position: t.Position}
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
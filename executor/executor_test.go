@@ -0,0 +1,482 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/executor"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const schemaSDL = `
+type Query {
+	user(id: ID!): User
+	users: [User!]
+	search(text: String!): [SearchResult]
+	node(id: ID!): Node
+	required: String!
+	status: Status
+	counter: Int
+	echo(value: Any): String
+}
+
+type Mutation {
+	increment(by: Int = 1): Int!
+	double: Int!
+}
+
+interface Node { id: ID! }
+
+type User implements Node {
+	id: ID!
+	name: String!
+	email: String
+	age: Int
+	friends: [User]
+	greeting(prefix: String = "Hello"): String
+}
+
+type Post implements Node {
+	id: ID!
+	title: String
+}
+
+union SearchResult = User | Post
+
+enum Status { ACTIVE INACTIVE }
+
+scalar Any
+`
+
+type User struct {
+	ID      string `json:"id"`
+	Name    *string
+	Mail    string `json:"email"`
+	Age     int64
+	Friends []*User
+}
+
+func (u *User) Greeting(ctx context.Context) (string, error) {
+	return "Hello " + *u.Name, nil
+}
+
+type Post struct {
+	ID    int
+	Title string
+}
+
+func resolved(value any) executor.ResolverFunc {
+	return func(ctx context.Context, p executor.ResolveParams) (any, error) {
+		return value, nil
+	}
+}
+
+func name(s string) *string {
+	return &s
+}
+
+func newExecutor(t *testing.T) (*executor.Executor, *ast.Schema) {
+	t.Helper()
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL})
+	return executor.NewExecutor(schema), schema
+}
+
+func execute(
+	t *testing.T,
+	e *executor.Executor,
+	schema *ast.Schema,
+	query string,
+	variables map[string]any,
+) *executor.Response {
+	t.Helper()
+	doc, errs := gqlparser.LoadQueryWithRules(schema, query, nil)
+	require.Empty(t, errs)
+	return e.Execute(context.Background(), &executor.Request{
+		Document:  doc,
+		Variables: variables,
+	})
+}
+
+// requireData checks that the data of resp is expected, field order included,
+// ignoring the whitespace of expected.
+func requireData(t *testing.T, expected string, resp *executor.Response) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, json.Compact(&buf, []byte(expected)))
+	data, err := json.Marshal(resp.Data)
+	require.NoError(t, err)
+	require.Equal(t, buf.String(), string(data))
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("default resolvers", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		alice := &User{ID: "1", Name: name("Alice"), Mail: "alice@example.com", Age: 30}
+		alice.Friends = []*User{{ID: "2", Name: name("Bob")}, nil}
+		e.AddResolver(
+			"Query.user",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				require.Equal(t, "1", p.Args["id"])
+				require.Equal(t, ast.Path{ast.PathName("me")}, p.Path)
+				return alice, nil
+			},
+		)
+		e.AddResolver("Query.status", resolved("ACTIVE"))
+
+		resp := execute(t, e, schema, `query ($id: ID!) {
+			__typename
+			me: user(id: $id) {
+				id name email age greeting
+				...on User { friends { name } }
+				friends { id }
+			}
+			status
+		}`, map[string]any{"id": "1"})
+
+		require.Empty(t, resp.Errors)
+		requireData(t, `{
+			"__typename": "Query",
+			"me": {
+				"id": "1",
+				"name": "Alice",
+				"email": "alice@example.com",
+				"age": 30,
+				"greeting": "Hello Alice",
+				"friends": [{"name": "Bob", "id": "2"}, null]
+			},
+			"status": "ACTIVE"
+		}`, resp)
+	})
+
+	t.Run("abstract types", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.AddResolver(
+			"Query.search",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				return []any{
+					&User{ID: "1", Name: name("Alice")},
+					Post{ID: 2, Title: "Hi"},
+					map[string]any{"__typename": "Post", "id": "3", "title": "Map"},
+				}, nil
+			},
+		)
+		e.AddResolver("Query.node", resolved(Post{ID: 4}))
+
+		resp := execute(t, e, schema, `{
+			search(text: "a") {
+				__typename
+				... on User { name }
+				... on Node { id }
+				... on Post { title }
+			}
+			node(id: "4") { id ... on User { name } }
+		}`, nil)
+
+		require.Empty(t, resp.Errors)
+		requireData(t, `{
+			"search": [
+				{"__typename": "User", "name": "Alice", "id": "1"},
+				{"__typename": "Post", "id": "2", "title": "Hi"},
+				{"__typename": "Post", "id": "3", "title": "Map"}
+			],
+			"node": {"id": "4"}
+		}`, resp)
+	})
+
+	t.Run("unresolvable abstract type", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.AddResolver("Query.node", resolved(map[string]any{"__typename": "Status"}))
+		resp := execute(t, e, schema, `{ node(id: "1") { id } }`, nil)
+		requireData(t, `{"node": null}`, resp)
+		require.Len(t, resp.Errors, 1)
+		require.Equal(
+			t,
+			`Abstract type "Node" was resolved to a type "Status" `+
+				`that does not exist inside the schema.`,
+			resp.Errors[0].Message,
+		)
+	})
+
+	t.Run("null bubbling", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		users := []*User{{ID: "1", Name: name("Alice")}, {ID: "2"}}
+		e.AddResolver("Query.users", resolved(users))
+		e.AddResolver("Query.user", resolved(&User{ID: "3"}))
+
+		resp := execute(t, e, schema, `{
+			users { id name }
+			user(id: "3") { id name }
+			status
+		}`, nil)
+
+		requireData(t, `{"users": null, "user": null, "status": null}`, resp)
+		require.Equal(t, gqlerror.List{
+			{
+				Message:   "Cannot return null for non-nullable field User.name.",
+				Path:      ast.Path{ast.PathName("user"), ast.PathName("name")},
+				Locations: []gqlerror.Location{{Line: 3, Column: 23}},
+			},
+			{
+				Message: "Cannot return null for non-nullable field User.name.",
+				Path: ast.Path{
+					ast.PathName("users"),
+					ast.PathIndex(1),
+					ast.PathName("name"),
+				},
+				Locations: []gqlerror.Location{{Line: 2, Column: 15}},
+			},
+		}, resp.Errors)
+	})
+
+	t.Run("null bubbles to data", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.AddResolver(
+			"Query.required",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				return nil, errors.New("unavailable")
+			},
+		)
+
+		resp := execute(t, e, schema, `{ status required }`, nil)
+		require.Nil(t, resp.Data)
+		require.Len(t, resp.Errors, 1)
+		require.Equal(t, "unavailable", resp.Errors[0].Message)
+		require.Equal(t, ast.Path{ast.PathName("required")}, resp.Errors[0].Path)
+	})
+
+	t.Run("resolver errors", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		codeErr := &gqlerror.Error{
+			Message:    "not found",
+			Extensions: map[string]any{"code": "NOT_FOUND"},
+		}
+		e.AddResolver(
+			"Query.user",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				return nil, codeErr
+			},
+		)
+		e.AddResolver(
+			"Query.node",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				panic("boom")
+			},
+		)
+		e.AddResolver("Query.counter", resolved(1.5))
+		e.AddResolver("Query.status", resolved("DELETED"))
+
+		resp := execute(t, e, schema, `{
+			a: user(id: "1") { id }
+			node(id: "1") { id }
+			counter
+			status
+		}`, nil)
+
+		requireData(t, `{"a": null, "node": null, "counter": null, "status": null}`, resp)
+		require.Len(t, resp.Errors, 4)
+		require.Equal(t, "not found", resp.Errors[0].Message)
+		require.Equal(t, map[string]any{"code": "NOT_FOUND"}, resp.Errors[0].Extensions)
+		require.Equal(t, ast.Path{ast.PathName("a")}, resp.Errors[0].Path)
+		require.Nil(t, codeErr.Path, "the returned error is not changed")
+		require.Equal(t, "Int cannot represent non-integer value: 1.5", resp.Errors[1].Message)
+		require.Equal(t, "panic in resolver: boom", resp.Errors[2].Message)
+		require.Equal(t, `Enum "Status" cannot represent value: "DELETED"`, resp.Errors[3].Message)
+	})
+
+	t.Run("type resolver panics", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.SetTypeResolver(
+			func(ctx context.Context, value any, abstractType *ast.Definition) (string, error) {
+				panic("boom")
+			},
+		)
+		e.AddResolver("Query.node", resolved(&User{ID: "1"}))
+		e.AddResolver("Query.status", resolved("ACTIVE"))
+
+		resp := execute(t, e, schema, `{ node(id: "1") { id } status }`, nil)
+		requireData(t, `{"node": null, "status": "ACTIVE"}`, resp)
+		require.Len(t, resp.Errors, 1)
+		require.Equal(t, "panic in type resolver: boom", resp.Errors[0].Message)
+		require.Equal(t, ast.Path{ast.PathName("node")}, resp.Errors[0].Path)
+	})
+
+	t.Run("errors ordered by path", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		friends := make([]*User, 11)
+		for i := range friends {
+			friends[i] = &User{ID: strconv.Itoa(i), Name: name("Friend")}
+		}
+		friends[2].Name = nil
+		friends[10].Name = nil
+		e.AddResolver("Query.user", resolved(&User{ID: "1", Friends: friends}))
+
+		resp := execute(t, e, schema, `{ user(id: "1") { friends { name } } }`, nil)
+		require.Len(t, resp.Errors, 2)
+		require.Equal(t, "user.friends[2].name", resp.Errors[0].Path.String())
+		require.Equal(t, "user.friends[10].name", resp.Errors[1].Path.String())
+	})
+
+	t.Run("invalid argument value", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.AddResolver("Query.status", resolved("ACTIVE"))
+		resp := execute(t, e, schema, `{
+			echo(value: 99999999999999999999)
+			status
+		}`, nil)
+		requireData(t, `{"echo": null, "status": "ACTIVE"}`, resp)
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, "invalid argument value")
+		require.Equal(t, ast.Path{ast.PathName("echo")}, resp.Errors[0].Path)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 4}}, resp.Errors[0].Locations)
+	})
+
+	t.Run("fields resolve serially by default", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		var running, concurrent atomic.Int32
+		count := func(ctx context.Context, p executor.ResolveParams) (any, error) {
+			if running.Add(1) > 1 {
+				concurrent.Add(1)
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return "ACTIVE", nil
+		}
+		e.AddResolver("Query.status", count)
+		e.AddResolver("Query.required", count)
+
+		resp := execute(t, e, schema, `{ status required a: status b: required }`, nil)
+		require.Empty(t, resp.Errors)
+		require.Zero(t, concurrent.Load())
+	})
+
+	t.Run("concurrency is bounded", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.SetConcurrency(2)
+		var mu sync.Mutex
+		running, most := 0, 0
+		count := func(ctx context.Context, p executor.ResolveParams) (any, error) {
+			mu.Lock()
+			running++
+			most = max(most, running)
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return "ACTIVE", nil
+		}
+		e.AddResolver("Query.status", count)
+		e.AddResolver("Query.required", count)
+
+		resp := execute(t, e, schema, `{
+			a: status b: required c: status d: required e: status f: required
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		// Two goroutines and the one calling Execute.
+		require.LessOrEqual(t, most, 3)
+	})
+
+	t.Run("query fields resolve in parallel", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.SetConcurrency(1)
+		var started sync.WaitGroup
+		started.Add(2)
+		wait := func(ctx context.Context, p executor.ResolveParams) (any, error) {
+			started.Done()
+			// Both resolvers must be running for either to finish.
+			started.Wait()
+			return "ACTIVE", nil
+		}
+		e.AddResolver("Query.status", wait)
+		e.AddResolver("Query.required", wait)
+
+		resp := execute(t, e, schema, `{ status required }`, nil)
+		require.Empty(t, resp.Errors)
+		requireData(t, `{"status": "ACTIVE", "required": "ACTIVE"}`, resp)
+	})
+
+	t.Run("mutation fields resolve serially", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		total := 0
+		e.AddResolver(
+			"Mutation.increment",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				total += int(p.Args["by"].(int64))
+				return total, nil
+			},
+		)
+		e.AddResolver(
+			"Mutation.double",
+			func(ctx context.Context, p executor.ResolveParams) (any, error) {
+				total *= 2
+				return total, nil
+			},
+		)
+
+		resp := execute(t, e, schema, `mutation {
+			a: increment
+			b: double
+			c: increment(by: 3)
+			d: double
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		requireData(t, `{"a": 1, "b": 2, "c": 5, "d": 10}`, resp)
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		resp := execute(t, e, schema, `{
+			__type(name: "Status") { enumValues { name } }
+			__schema { mutationType { name } }
+		}`, nil)
+		require.Empty(t, resp.Errors)
+		requireData(t, `{
+			"__type": {"enumValues": [{"name": "ACTIVE"}, {"name": "INACTIVE"}]},
+			"__schema": {"mutationType": {"name": "Mutation"}}
+		}`, resp)
+	})
+
+	t.Run("skip and include", func(t *testing.T) {
+		e, schema := newExecutor(t)
+		e.AddResolver("Query.status", resolved("ACTIVE"))
+		resp := execute(t, e, schema, `query ($skip: Boolean!) {
+			status @skip(if: $skip)
+			s: status @include(if: $skip)
+		}`, map[string]any{"skip": true})
+		require.Empty(t, resp.Errors)
+		requireData(t, `{"s": "ACTIVE"}`, resp)
+	})
+}
+
+func TestExecuteRequestErrors(t *testing.T) {
+	e, schema := newExecutor(t)
+
+	resp := execute(t, e, schema, `query ($id: ID!) { user(id: $id) { id } }`, nil)
+	require.Nil(t, resp.Data)
+	require.EqualError(t, resp.Errors, "input: variable.id must be defined\n")
+
+	doc, errs := gqlparser.LoadQueryWithRules(schema, `query A { status } query B { status }`, nil)
+	require.Empty(t, errs)
+	resp = e.Execute(context.Background(), &executor.Request{Document: doc})
+	require.Nil(t, resp.Data)
+	require.Equal(
+		t,
+		"Must provide operation name if query contains multiple operations.",
+		resp.Errors[0].Message,
+	)
+
+	resp = e.Execute(context.Background(), &executor.Request{Document: doc, OperationName: "B"})
+	require.Empty(t, resp.Errors)
+	requireData(t, `{"status": null}`, resp)
+}
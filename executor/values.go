@@ -0,0 +1,306 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// DefaultResolver resolves a field from a property of its source with the
+// same name. The source may be a map with string keys, or a struct or a
+// pointer to one, whose exported fields are matched by their json tag or
+// else by name ignoring case. A method matching the same way is called
+// instead when it takes nothing or a context.Context, and returns a value
+// and optionally an error.
+func DefaultResolver(ctx context.Context, params ResolveParams) (any, error) {
+	name := params.Field.Name
+	if m, ok := params.Source.(map[string]any); ok {
+		return m[name], nil
+	}
+
+	v := reflect.ValueOf(params.Source)
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if method := findMethod(v, name); method.IsValid() {
+		return callMethod(ctx, method)
+	}
+
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, nil
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return value.Interface(), nil
+	case reflect.Struct:
+		if field := findField(v, name); field.IsValid() {
+			return field.Interface(), nil
+		}
+	}
+	return nil, nil
+}
+
+func findMethod(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if !strings.EqualFold(method.Name, name) {
+			continue
+		}
+		mt := method.Type
+		// The receiver is the first input.
+		takesNothing := mt.NumIn() == 1
+		takesContext := mt.NumIn() == 2 && mt.In(1) == contextType
+		returnsValue := mt.NumOut() == 1 && mt.Out(0) != errorType
+		returnsError := mt.NumOut() == 2 && mt.Out(1) == errorType
+		if (takesNothing || takesContext) && (returnsValue || returnsError) {
+			return v.Method(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func callMethod(ctx context.Context, method reflect.Value) (any, error) {
+	var in []reflect.Value
+	if method.Type().NumIn() == 1 {
+		in = []reflect.Value{reflect.ValueOf(ctx)}
+	}
+	out := method.Call(in)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+	return out[0].Interface(), nil
+}
+
+func findField(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	byName := -1
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == name {
+			return v.Field(i)
+		}
+		if byName < 0 && strings.EqualFold(field.Name, name) {
+			byName = i
+		}
+	}
+	if byName >= 0 {
+		return v.Field(byName)
+	}
+	return reflect.Value{}
+}
+
+// DefaultTypeResolver finds the object type of a value returned for an
+// interface or union from its __typename key when it is a map, or else from
+// the name of its Go type.
+func DefaultTypeResolver(_ context.Context, value any, _ *ast.Definition) (string, error) {
+	if m, ok := value.(map[string]any); ok {
+		typename, _ := m["__typename"].(string)
+		return typename, nil
+	}
+
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name(), nil
+}
+
+// serializeLeaf is the result coercion of a scalar or enum. The built-in
+// scalars are coerced like graphql-js does, while custom scalars are left
+// as they are.
+func serializeLeaf(def *ast.Definition, value any) (any, error) {
+	if def.Kind == ast.Enum {
+		return serializeEnum(def, value)
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if number, ok := value.(json.Number); ok {
+		if f, err := number.Float64(); err == nil {
+			v = reflect.ValueOf(f)
+		}
+	}
+
+	switch def.Name {
+	case "Int":
+		return serializeInt(v, value)
+	case "Float":
+		return serializeFloat(v, value)
+	case "String":
+		return serializeString(v, value)
+	case "Boolean":
+		return serializeBoolean(v, value)
+	case "ID":
+		return serializeID(v, value)
+	}
+	return value, nil
+}
+
+func serializeInt(v reflect.Value, value any) (any, error) {
+	var n float64
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return nil, gqlerror.Errorf("Int cannot represent non-integer value: %v", value)
+		}
+		n = f
+	default:
+		return nil, gqlerror.Errorf("Int cannot represent non-integer value: %v", value)
+	}
+	if n != math.Trunc(n) {
+		return nil, gqlerror.Errorf("Int cannot represent non-integer value: %v", value)
+	}
+	if n > math.MaxInt32 || n < math.MinInt32 {
+		return nil, gqlerror.Errorf(
+			"Int cannot represent non 32-bit signed integer value: %v",
+			value,
+		)
+	}
+	return int(n), nil
+}
+
+func serializeFloat(v reflect.Value, value any) (any, error) {
+	var n float64
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return nil, gqlerror.Errorf("Float cannot represent non numeric value: %v", value)
+		}
+		n = f
+	default:
+		return nil, gqlerror.Errorf("Float cannot represent non numeric value: %v", value)
+	}
+	if math.IsInf(n, 0) || math.IsNaN(n) {
+		return nil, gqlerror.Errorf("Float cannot represent non numeric value: %v", value)
+	}
+	return n, nil
+}
+
+func serializeString(v reflect.Value, value any) (any, error) {
+	if s, ok := value.(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	}
+	return nil, gqlerror.Errorf("String cannot represent value: %v", value)
+}
+
+func serializeBoolean(v reflect.Value, value any) (any, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		if !math.IsInf(v.Float(), 0) && !math.IsNaN(v.Float()) {
+			return v.Float() != 0, nil
+		}
+	}
+	return nil, gqlerror.Errorf("Boolean cannot represent a non boolean value: %v", value)
+}
+
+func serializeID(v reflect.Value, value any) (any, error) {
+	if s, ok := value.(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	}
+	return nil, gqlerror.Errorf("ID cannot represent value: %v", value)
+}
+
+// serializeEnum accepts any value with a string kind, or a fmt.Stringer, that
+// names one of the values of def.
+func serializeEnum(def *ast.Definition, value any) (any, error) {
+	var name string
+	if s, ok := value.(fmt.Stringer); ok {
+		name = s.String()
+	} else if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+		name = v.String()
+	} else {
+		return nil, gqlerror.Errorf(
+			`Enum "%s" cannot represent non-string value: %v`,
+			def.Name,
+			value,
+		)
+	}
+	if def.EnumValues.ForName(name) == nil {
+		return nil, gqlerror.Errorf(`Enum "%s" cannot represent value: "%s"`, def.Name, name)
+	}
+	return name, nil
+}
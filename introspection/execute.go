@@ -0,0 +1,437 @@
+package introspection
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Execute resolves the introspection fields of an operation in doc, which must
+// have been validated against schema. operationName may be empty when doc has
+// a single operation.
+//
+// The returned data has an entry for each __schema, __type and __typename
+// selected on the root type, keyed by response name and in the order they were
+// selected. Other root fields are left out, so a server can resolve them itself
+// and merge the results.
+func Execute(
+	schema *ast.Schema,
+	doc *ast.QueryDocument,
+	operationName string,
+	variables map[string]any,
+) (ast.ResultMap, error) {
+	op, err := getOperation(doc, operationName)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *ast.Definition
+	switch op.Operation {
+	case ast.Mutation:
+		root = schema.Mutation
+	case ast.Subscription:
+		root = schema.Subscription
+	default:
+		root = schema.Query
+	}
+	if root == nil {
+		return nil, gqlerror.ErrorPosf(
+			op.Position,
+			"Schema is not configured to execute %s operation.",
+			op.Operation,
+		)
+	}
+
+	e := &executor{schema: schema, variables: variables}
+	data := ast.ResultMap{}
+	for _, f := range ast.CollectFields(schema, op.SelectionSet, root, variables) {
+		switch f.Fields[0].Name {
+		case "__typename":
+			data = append(data, ast.ResultField{Key: f.ResponseKey, Value: root.Name})
+		case "__schema", "__type":
+			data = append(data, ast.ResultField{Key: f.ResponseKey, Value: e.rootField(f)})
+		}
+	}
+	return data, nil
+}
+
+// ResolveField resolves a __schema or __type field selected on the root type
+// of an operation, as collected by ast.CollectFields. It is for servers that
+// execute the rest of the operation themselves, and returns nil for any other
+// field.
+func ResolveField(schema *ast.Schema, field *ast.CollectedField, variables map[string]any) any {
+	e := &executor{schema: schema, variables: variables}
+	return e.rootField(field)
+}
+
+func (e *executor) rootField(f *ast.CollectedField) any {
+	switch f.Fields[0].Name {
+	case "__schema":
+		return e.schemaObject(f.SelectionSet())
+	case "__type":
+		name, _ := f.Fields[0].ArgumentMap(e.variables)["name"].(string)
+		if def := e.schema.Types[name]; def != nil {
+			return e.typeObject(ast.NamedType(def.Name, nil), f.SelectionSet())
+		}
+	}
+	return nil
+}
+
+func getOperation(doc *ast.QueryDocument, operationName string) (*ast.OperationDefinition, error) {
+	if operationName == "" {
+		switch len(doc.Operations) {
+		case 0:
+			return nil, gqlerror.Errorf("Must provide an operation.")
+		case 1:
+			return doc.Operations[0], nil
+		default:
+			return nil, gqlerror.Errorf(
+				"Must provide operation name if query contains multiple operations.",
+			)
+		}
+	}
+	if op := doc.Operations.ForName(operationName); op != nil {
+		return op, nil
+	}
+	return nil, gqlerror.Errorf(`Unknown operation named "%s".`, operationName)
+}
+
+type executor struct {
+	schema    *ast.Schema
+	variables map[string]any
+}
+
+// object resolves selectionSet against a value of the introspection type
+// typename, with resolve giving the value of each of its fields.
+func (e *executor) object(
+	selectionSet ast.SelectionSet,
+	typename string,
+	resolve func(field *ast.Field, selectionSet ast.SelectionSet) any,
+) ast.ResultMap {
+	objectType := e.schema.Types[typename]
+	if objectType == nil {
+		// Schemas built by hand may leave out the introspection types.
+		objectType = &ast.Definition{Kind: ast.Object, Name: typename}
+	}
+	fields := ast.CollectFields(e.schema, selectionSet, objectType, e.variables)
+	result := make(ast.ResultMap, 0, len(fields))
+	for _, f := range fields {
+		value := any(typename)
+		if f.Fields[0].Name != "__typename" {
+			value = resolve(f.Fields[0], f.SelectionSet())
+		}
+		result = append(result, ast.ResultField{Key: f.ResponseKey, Value: value})
+	}
+	return result
+}
+
+func (e *executor) includeDeprecated(field *ast.Field) bool {
+	includeDeprecated, _ := field.ArgumentMap(e.variables)["includeDeprecated"].(bool)
+	return includeDeprecated
+}
+
+func (e *executor) schemaObject(selectionSet ast.SelectionSet) ast.ResultMap {
+	return e.object(selectionSet, "__Schema", func(field *ast.Field, sel ast.SelectionSet) any {
+		switch field.Name {
+		case "description":
+			return nullableString(e.schema.Description)
+		case "types":
+			types := sortedTypes(e.schema)
+			result := make([]any, 0, len(types))
+			for _, def := range types {
+				result = append(result, e.typeObject(ast.NamedType(def.Name, nil), sel))
+			}
+			return result
+		case "queryType":
+			return e.rootTypeObject(e.schema.Query, sel)
+		case "mutationType":
+			return e.rootTypeObject(e.schema.Mutation, sel)
+		case "subscriptionType":
+			return e.rootTypeObject(e.schema.Subscription, sel)
+		case "directives":
+			directives := sortedDirectives(e.schema)
+			result := make([]any, 0, len(directives))
+			for _, def := range directives {
+				result = append(result, e.directiveObject(def, sel))
+			}
+			return result
+		}
+		return nil
+	})
+}
+
+func (e *executor) rootTypeObject(def *ast.Definition, selectionSet ast.SelectionSet) any {
+	if def == nil {
+		return nil
+	}
+	return e.typeObject(ast.NamedType(def.Name, nil), selectionSet)
+}
+
+// typeObject resolves a __Type, which is either a named type or a list or
+// non-null wrapping another type.
+func (e *executor) typeObject(t *ast.Type, selectionSet ast.SelectionSet) ast.ResultMap {
+	var def *ast.Definition
+	if !t.NonNull && t.Elem == nil {
+		def = e.schema.Types[t.NamedType]
+	}
+
+	return e.object(selectionSet, "__Type", func(field *ast.Field, sel ast.SelectionSet) any {
+		switch field.Name {
+		case "kind":
+			switch {
+			case t.NonNull:
+				return "NON_NULL"
+			case t.Elem != nil:
+				return "LIST"
+			case def != nil:
+				return string(def.Kind)
+			}
+		case "ofType":
+			switch {
+			case t.NonNull:
+				return e.typeObject(&ast.Type{NamedType: t.NamedType, Elem: t.Elem}, sel)
+			case t.Elem != nil:
+				return e.typeObject(t.Elem, sel)
+			}
+		}
+		if def == nil {
+			return nil
+		}
+
+		switch field.Name {
+		case "name":
+			return def.Name
+		case "description":
+			return nullableString(def.Description)
+		case "specifiedByURL":
+			if def.Kind != ast.Scalar {
+				return nil
+			}
+			if dir := def.Directives.ForName("specifiedBy"); dir != nil {
+				if arg := dir.Arguments.ForName("url"); arg != nil {
+					return arg.Value.Raw
+				}
+			}
+		case "isOneOf":
+			if def.Kind == ast.InputObject {
+				return def.Directives.ForName("oneOf") != nil
+			}
+		case "fields":
+			if def.Kind != ast.Object && def.Kind != ast.Interface {
+				return nil
+			}
+			includeDeprecated := e.includeDeprecated(field)
+			result := make([]any, 0, len(def.Fields))
+			for _, f := range def.Fields {
+				if strings.HasPrefix(f.Name, "__") {
+					continue
+				}
+				isDeprecated, _ := deprecation(f.Directives)
+				if isDeprecated && !includeDeprecated {
+					continue
+				}
+				result = append(result, e.fieldObject(f, sel))
+			}
+			return result
+		case "interfaces":
+			if def.Kind != ast.Object && def.Kind != ast.Interface {
+				return nil
+			}
+			result := make([]any, 0, len(def.Interfaces))
+			for _, name := range def.Interfaces {
+				result = append(result, e.typeObject(ast.NamedType(name, nil), sel))
+			}
+			return result
+		case "possibleTypes":
+			if def.Kind != ast.Interface && def.Kind != ast.Union {
+				return nil
+			}
+			objects := possibleObjectTypes(e.schema, def)
+			result := make([]any, 0, len(objects))
+			for _, possible := range objects {
+				result = append(result, e.typeObject(ast.NamedType(possible.Name, nil), sel))
+			}
+			return result
+		case "enumValues":
+			if def.Kind != ast.Enum {
+				return nil
+			}
+			includeDeprecated := e.includeDeprecated(field)
+			result := make([]any, 0, len(def.EnumValues))
+			for _, value := range def.EnumValues {
+				isDeprecated, _ := deprecation(value.Directives)
+				if isDeprecated && !includeDeprecated {
+					continue
+				}
+				result = append(result, e.enumValueObject(value, sel))
+			}
+			return result
+		case "inputFields":
+			if def.Kind != ast.InputObject {
+				return nil
+			}
+			includeDeprecated := e.includeDeprecated(field)
+			result := make([]any, 0, len(def.Fields))
+			for _, f := range def.Fields {
+				isDeprecated, _ := deprecation(f.Directives)
+				if isDeprecated && !includeDeprecated {
+					continue
+				}
+				result = append(result, e.inputValueObject(inputValue{
+					name:         f.Name,
+					description:  f.Description,
+					typ:          f.Type,
+					defaultValue: f.DefaultValue,
+					directives:   f.Directives,
+				}, sel))
+			}
+			return result
+		}
+		return nil
+	})
+}
+
+func (e *executor) fieldObject(
+	def *ast.FieldDefinition,
+	selectionSet ast.SelectionSet,
+) ast.ResultMap {
+	return e.object(selectionSet, "__Field", func(field *ast.Field, sel ast.SelectionSet) any {
+		switch field.Name {
+		case "name":
+			return def.Name
+		case "description":
+			return nullableString(def.Description)
+		case "args":
+			return e.argsList(def.Arguments, e.includeDeprecated(field), sel)
+		case "type":
+			return e.typeObject(def.Type, sel)
+		case "isDeprecated":
+			isDeprecated, _ := deprecation(def.Directives)
+			return isDeprecated
+		case "deprecationReason":
+			_, reason := deprecation(def.Directives)
+			return nullableReason(reason)
+		}
+		return nil
+	})
+}
+
+// inputValue is an argument or an input field, which are both a __InputValue.
+type inputValue struct {
+	name         string
+	description  string
+	typ          *ast.Type
+	defaultValue *ast.Value
+	directives   ast.DirectiveList
+}
+
+func (e *executor) argsList(
+	arguments ast.ArgumentDefinitionList,
+	includeDeprecated bool,
+	selectionSet ast.SelectionSet,
+) []any {
+	result := make([]any, 0, len(arguments))
+	for _, arg := range arguments {
+		if isDeprecated, _ := deprecation(arg.Directives); isDeprecated && !includeDeprecated {
+			continue
+		}
+		result = append(result, e.inputValueObject(inputValue{
+			name:         arg.Name,
+			description:  arg.Description,
+			typ:          arg.Type,
+			defaultValue: arg.DefaultValue,
+			directives:   arg.Directives,
+		}, selectionSet))
+	}
+	return result
+}
+
+func (e *executor) inputValueObject(
+	value inputValue,
+	selectionSet ast.SelectionSet,
+) ast.ResultMap {
+	return e.object(selectionSet, "__InputValue", func(field *ast.Field, sel ast.SelectionSet) any {
+		switch field.Name {
+		case "name":
+			return value.name
+		case "description":
+			return nullableString(value.description)
+		case "type":
+			return e.typeObject(value.typ, sel)
+		case "defaultValue":
+			if value.defaultValue == nil {
+				return nil
+			}
+			return PrintValue(value.defaultValue)
+		case "isDeprecated":
+			isDeprecated, _ := deprecation(value.directives)
+			return isDeprecated
+		case "deprecationReason":
+			_, reason := deprecation(value.directives)
+			return nullableReason(reason)
+		}
+		return nil
+	})
+}
+
+func (e *executor) enumValueObject(
+	value *ast.EnumValueDefinition,
+	selectionSet ast.SelectionSet,
+) ast.ResultMap {
+	return e.object(selectionSet, "__EnumValue", func(field *ast.Field, _ ast.SelectionSet) any {
+		switch field.Name {
+		case "name":
+			return value.Name
+		case "description":
+			return nullableString(value.Description)
+		case "isDeprecated":
+			isDeprecated, _ := deprecation(value.Directives)
+			return isDeprecated
+		case "deprecationReason":
+			_, reason := deprecation(value.Directives)
+			return nullableReason(reason)
+		}
+		return nil
+	})
+}
+
+func (e *executor) directiveObject(
+	def *ast.DirectiveDefinition,
+	selectionSet ast.SelectionSet,
+) ast.ResultMap {
+	return e.object(selectionSet, "__Directive", func(field *ast.Field, sel ast.SelectionSet) any {
+		switch field.Name {
+		case "name":
+			return def.Name
+		case "description":
+			return nullableString(def.Description)
+		case "isRepeatable":
+			return def.IsRepeatable
+		case "locations":
+			result := make([]any, 0, len(def.Locations))
+			for _, location := range def.Locations {
+				result = append(result, string(location))
+			}
+			return result
+		case "args":
+			return e.argsList(def.Arguments, e.includeDeprecated(field), sel)
+		}
+		return nil
+	})
+}
+
+// nullableString returns nil for an empty s, so it is null in the response.
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullableReason(reason *string) any {
+	if reason == nil {
+		return nil
+	}
+	return *reason
+}
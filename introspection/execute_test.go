@@ -0,0 +1,183 @@
+package introspection_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/introspection"
+)
+
+// execute returns the JSON of the data introspection.Execute returns.
+func execute(
+	t *testing.T,
+	schema *ast.Schema,
+	query string,
+	operationName string,
+	variables map[string]any,
+) string {
+	t.Helper()
+	doc, errs := gqlparser.LoadQueryWithRules(schema, query, nil)
+	require.Empty(t, errs)
+	data, err := introspection.Execute(schema, doc, operationName, variables)
+	require.NoError(t, err)
+	result, err := json.Marshal(data)
+	require.NoError(t, err)
+	return string(result)
+}
+
+// requireJSON checks that actual is expected, field order included, ignoring
+// the whitespace of expected.
+func requireJSON(t *testing.T, expected string, actual string) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, json.Compact(&buf, []byte(expected)))
+	require.Equal(t, buf.String(), actual)
+}
+
+func TestExecute(t *testing.T) {
+	schema := loadSchema(t)
+
+	t.Run("full introspection query matches FromSchema", func(t *testing.T) {
+		query, err := os.ReadFile("testdata/introspection.graphql")
+		require.NoError(t, err)
+		data := execute(t, schema, string(query), "", nil)
+
+		expected, err := json.Marshal(introspection.FromSchema(schema))
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), data)
+	})
+
+	t.Run("__type with aliases and __typename", func(t *testing.T) {
+		data := execute(t, schema, `{
+			__typename
+			user: __type(name: "User") {
+				__typename
+				kind
+				name
+				fields { name type { name kind ofType { name } } }
+			}
+			missing: __type(name: "Missing") { name }
+			search { __typename }
+		}`, "", nil)
+
+		requireJSON(t, `{
+			"__typename": "Query",
+			"user": {
+				"__typename": "__Type",
+				"kind": "OBJECT",
+				"name": "User",
+				"fields": [
+					{
+						"name": "id",
+						"type": {"name": null, "kind": "NON_NULL", "ofType": {"name": "ID"}}
+					},
+					{
+						"name": "created",
+						"type": {"name": "DateTime", "kind": "SCALAR", "ofType": null}
+					},
+					{
+						"name": "status",
+						"type": {"name": "Status", "kind": "ENUM", "ofType": null}
+					}
+				]
+			},
+			"missing": null
+		}`, data)
+	})
+
+	t.Run("selection order", func(t *testing.T) {
+		data := execute(t, schema, `{
+			z: __typename
+			__type(name: "Status") { name kind b: __typename a: name }
+			a: __typename
+		}`, "", nil)
+
+		requireJSON(t, `{
+			"z": "Query",
+			"__type": {"name": "Status", "kind": "ENUM", "b": "__Type", "a": "Status"},
+			"a": "Query"
+		}`, data)
+	})
+
+	t.Run("includeDeprecated", func(t *testing.T) {
+		data := execute(t, schema, `query ($all: Boolean!) {
+			query: __type(name: "Query") {
+				fields(includeDeprecated: $all) { name }
+				all: fields(includeDeprecated: true) { name isDeprecated }
+			}
+			status: __type(name: "Status") { enumValues { name } }
+			filter: __type(name: "Filter") { inputFields { name } }
+		}`, "", map[string]any{"all": false})
+
+		requireJSON(t, `{
+			"query": {
+				"fields": [{"name": "node"}, {"name": "search"}],
+				"all": [
+					{"name": "node", "isDeprecated": false},
+					{"name": "search", "isDeprecated": false},
+					{"name": "users", "isDeprecated": true},
+					{"name": "legacy", "isDeprecated": true}
+				]
+			},
+			"status": {"enumValues": [{"name": "ACTIVE"}]},
+			"filter": {"inputFields": [{"name": "status"}, {"name": "by"}]}
+		}`, data)
+	})
+
+	t.Run("fragments, skip and include", func(t *testing.T) {
+		data := execute(t, schema, `
+			query Other { __typename }
+			query Introspect($skip: Boolean!) {
+				... on Query { __schema { ...Roots } }
+				...Typename @skip(if: $skip)
+				__type(name: "Node") @include(if: false) { name }
+			}
+			fragment Roots on __Schema {
+				queryType { name }
+				mutationType { ... on __Type { name } }
+				subscriptionType { name }
+				queryType { kind }
+			}
+			fragment Typename on Query { __typename }
+		`, "Introspect", map[string]any{"skip": true})
+
+		requireJSON(t, `{
+			"__schema": {
+				"queryType": {"name": "Query", "kind": "OBJECT"},
+				"mutationType": {"name": "Mutation"},
+				"subscriptionType": null
+			}
+		}`, data)
+	})
+
+	t.Run("mutation __typename", func(t *testing.T) {
+		data := execute(t, schema, `mutation { __typename }`, "", nil)
+		requireJSON(t, `{"__typename": "Mutation"}`, data)
+	})
+}
+
+func TestExecuteOperationErrors(t *testing.T) {
+	schema := loadSchema(t)
+	doc, errs := gqlparser.LoadQueryWithRules(
+		schema,
+		`query A { __typename } query B { __typename }`,
+		nil,
+	)
+	require.Empty(t, errs)
+
+	_, err := introspection.Execute(schema, doc, "", nil)
+	require.EqualError(
+		t,
+		err,
+		"input: Must provide operation name if query contains multiple operations.",
+	)
+
+	_, err = introspection.Execute(schema, doc, "C", nil)
+	require.EqualError(t, err, `input: Unknown operation named "C".`)
+}
@@ -0,0 +1,359 @@
+// Package introspection describes a schema the way its introspection types do,
+// so a server can answer introspection without a resolver for every field of
+// __Schema and __Type.
+package introspection
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Result is the data returned for the full introspection query, in the shape
+// of the IntrospectionQuery type of graphql-js.
+type Result struct {
+	Schema Schema `json:"__schema"`
+}
+
+type Schema struct {
+	Description      *string       `json:"description"`
+	QueryType        *NamedTypeRef `json:"queryType"`
+	MutationType     *NamedTypeRef `json:"mutationType"`
+	SubscriptionType *NamedTypeRef `json:"subscriptionType"`
+	Types            []FullType    `json:"types"`
+	Directives       []Directive   `json:"directives"`
+}
+
+type NamedTypeRef struct {
+	Name string `json:"name"`
+}
+
+// FullType is a named type. The fields that do not apply to its kind are nil,
+// which is null once marshalled, as introspection reports them.
+type FullType struct {
+	Kind           string       `json:"kind"`
+	Name           string       `json:"name"`
+	Description    *string      `json:"description"`
+	SpecifiedByURL *string      `json:"specifiedByURL"`
+	IsOneOf        *bool        `json:"isOneOf"`
+	Fields         []Field      `json:"fields"`
+	InputFields    []InputValue `json:"inputFields"`
+	Interfaces     []TypeRef    `json:"interfaces"`
+	EnumValues     []EnumValue  `json:"enumValues"`
+	PossibleTypes  []TypeRef    `json:"possibleTypes"`
+}
+
+// TypeRef is a reference to a type, which is either named or wraps OfType in
+// a LIST or NON_NULL.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+type Field struct {
+	Name              string       `json:"name"`
+	Description       *string      `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+type InputValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	Type              TypeRef `json:"type"`
+	DefaultValue      *string `json:"defaultValue"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type Directive struct {
+	Name         string                  `json:"name"`
+	Description  *string                 `json:"description"`
+	IsRepeatable bool                    `json:"isRepeatable"`
+	Locations    []ast.DirectiveLocation `json:"locations"`
+	Args         []InputValue            `json:"args"`
+}
+
+// defaultDeprecationReason is the default of the reason argument of
+// @deprecated.
+const defaultDeprecationReason = "No longer supported"
+
+// FromSchema returns the result of the full introspection query against
+// schema, like introspectionFromSchema in graphql-js. Deprecated fields,
+// arguments, input fields and enum values are all included. Types and
+// directives are sorted by name: graphql-js lists them in the order the
+// schema defines them, but ast.Schema keeps them in maps, which have none.
+func FromSchema(schema *ast.Schema) *Result {
+	result := &Result{
+		Schema: Schema{
+			Description:      description(schema.Description),
+			QueryType:        namedTypeRef(schema.Query),
+			MutationType:     namedTypeRef(schema.Mutation),
+			SubscriptionType: namedTypeRef(schema.Subscription),
+			Types:            make([]FullType, 0, len(schema.Types)),
+			Directives:       make([]Directive, 0, len(schema.Directives)),
+		},
+	}
+
+	for _, def := range sortedTypes(schema) {
+		result.Schema.Types = append(result.Schema.Types, fullType(schema, def))
+	}
+	for _, def := range sortedDirectives(schema) {
+		result.Schema.Directives = append(result.Schema.Directives, directive(schema, def))
+	}
+
+	return result
+}
+
+func sortedTypes(schema *ast.Schema) []*ast.Definition {
+	types := make([]*ast.Definition, 0, len(schema.Types))
+	for _, def := range schema.Types {
+		types = append(types, def)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+	return types
+}
+
+func sortedDirectives(schema *ast.Schema) []*ast.DirectiveDefinition {
+	directives := make([]*ast.DirectiveDefinition, 0, len(schema.Directives))
+	for _, def := range schema.Directives {
+		directives = append(directives, def)
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	return directives
+}
+
+func namedTypeRef(def *ast.Definition) *NamedTypeRef {
+	if def == nil {
+		return nil
+	}
+	return &NamedTypeRef{Name: def.Name}
+}
+
+func fullType(schema *ast.Schema, def *ast.Definition) FullType {
+	t := FullType{
+		Kind:        string(def.Kind),
+		Name:        def.Name,
+		Description: description(def.Description),
+	}
+
+	switch def.Kind {
+	case ast.Scalar:
+		if dir := def.Directives.ForName("specifiedBy"); dir != nil {
+			if arg := dir.Arguments.ForName("url"); arg != nil {
+				url := arg.Value.Raw
+				t.SpecifiedByURL = &url
+			}
+		}
+	case ast.Object, ast.Interface:
+		t.Fields = fields(schema, def, true)
+		t.Interfaces = interfaces(schema, def)
+		if def.Kind == ast.Interface {
+			t.PossibleTypes = possibleTypes(schema, def)
+		}
+	case ast.Union:
+		t.PossibleTypes = possibleTypes(schema, def)
+	case ast.Enum:
+		t.EnumValues = enumValues(def, true)
+	case ast.InputObject:
+		isOneOf := def.Directives.ForName("oneOf") != nil
+		t.IsOneOf = &isOneOf
+		t.InputFields = inputFields(schema, def, true)
+	}
+
+	return t
+}
+
+// fields returns the fields of an object or interface, leaving out the
+// introspection fields such as __schema that are added to Query.
+func fields(schema *ast.Schema, def *ast.Definition, includeDeprecated bool) []Field {
+	result := make([]Field, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		isDeprecated, reason := deprecation(field.Directives)
+		if isDeprecated && !includeDeprecated {
+			continue
+		}
+		result = append(result, Field{
+			Name:              field.Name,
+			Description:       description(field.Description),
+			Args:              args(schema, field.Arguments, includeDeprecated),
+			Type:              typeRef(schema, field.Type),
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: reason,
+		})
+	}
+	return result
+}
+
+func inputFields(schema *ast.Schema, def *ast.Definition, includeDeprecated bool) []InputValue {
+	result := make([]InputValue, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		isDeprecated, reason := deprecation(field.Directives)
+		if isDeprecated && !includeDeprecated {
+			continue
+		}
+		result = append(result, InputValue{
+			Name:              field.Name,
+			Description:       description(field.Description),
+			Type:              typeRef(schema, field.Type),
+			DefaultValue:      defaultValue(field.DefaultValue),
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: reason,
+		})
+	}
+	return result
+}
+
+func args(
+	schema *ast.Schema,
+	arguments ast.ArgumentDefinitionList,
+	includeDeprecated bool,
+) []InputValue {
+	result := make([]InputValue, 0, len(arguments))
+	for _, arg := range arguments {
+		isDeprecated, reason := deprecation(arg.Directives)
+		if isDeprecated && !includeDeprecated {
+			continue
+		}
+		result = append(result, InputValue{
+			Name:              arg.Name,
+			Description:       description(arg.Description),
+			Type:              typeRef(schema, arg.Type),
+			DefaultValue:      defaultValue(arg.DefaultValue),
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: reason,
+		})
+	}
+	return result
+}
+
+func enumValues(def *ast.Definition, includeDeprecated bool) []EnumValue {
+	result := make([]EnumValue, 0, len(def.EnumValues))
+	for _, value := range def.EnumValues {
+		isDeprecated, reason := deprecation(value.Directives)
+		if isDeprecated && !includeDeprecated {
+			continue
+		}
+		result = append(result, EnumValue{
+			Name:              value.Name,
+			Description:       description(value.Description),
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: reason,
+		})
+	}
+	return result
+}
+
+func interfaces(schema *ast.Schema, def *ast.Definition) []TypeRef {
+	result := make([]TypeRef, 0, len(def.Interfaces))
+	for _, name := range def.Interfaces {
+		result = append(result, typeRef(schema, ast.NamedType(name, nil)))
+	}
+	return result
+}
+
+func possibleTypes(schema *ast.Schema, def *ast.Definition) []TypeRef {
+	objects := possibleObjectTypes(schema, def)
+	result := make([]TypeRef, 0, len(objects))
+	for _, possible := range objects {
+		result = append(result, typeRef(schema, ast.NamedType(possible.Name, nil)))
+	}
+	return result
+}
+
+// possibleObjectTypes returns the object types of an interface or union.
+// Interfaces implementing an interface are among its possible types in
+// ast.Schema, but are not reported by introspection.
+func possibleObjectTypes(schema *ast.Schema, def *ast.Definition) []*ast.Definition {
+	var result []*ast.Definition
+	for _, possible := range schema.GetPossibleTypes(def) {
+		if possible.Kind == ast.Object {
+			result = append(result, possible)
+		}
+	}
+	return result
+}
+
+func directive(schema *ast.Schema, def *ast.DirectiveDefinition) Directive {
+	locations := def.Locations
+	if locations == nil {
+		locations = []ast.DirectiveLocation{}
+	}
+	return Directive{
+		Name:         def.Name,
+		Description:  description(def.Description),
+		IsRepeatable: def.IsRepeatable,
+		Locations:    locations,
+		Args:         args(schema, def.Arguments, true),
+	}
+}
+
+func typeRef(schema *ast.Schema, t *ast.Type) TypeRef {
+	if t.NonNull {
+		ofType := typeRef(schema, &ast.Type{NamedType: t.NamedType, Elem: t.Elem})
+		return TypeRef{Kind: "NON_NULL", OfType: &ofType}
+	}
+	if t.NamedType == "" && t.Elem != nil {
+		ofType := typeRef(schema, t.Elem)
+		return TypeRef{Kind: "LIST", OfType: &ofType}
+	}
+
+	name := t.NamedType
+	ref := TypeRef{Name: &name}
+	if def := schema.Types[name]; def != nil {
+		ref.Kind = string(def.Kind)
+	}
+	return ref
+}
+
+// deprecation reports whether directives include @deprecated, and the reason
+// given for it.
+func deprecation(directives ast.DirectiveList) (bool, *string) {
+	dir := directives.ForName("deprecated")
+	if dir == nil {
+		return false, nil
+	}
+	arg := dir.Arguments.ForName("reason")
+	if arg == nil {
+		reason := defaultDeprecationReason
+		return true, &reason
+	}
+	if arg.Value.Kind == ast.NullValue {
+		return true, nil
+	}
+	reason := arg.Value.Raw
+	return true, &reason
+}
+
+func description(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func defaultValue(value *ast.Value) *string {
+	if value == nil {
+		return nil
+	}
+	s := PrintValue(value)
+	return &s
+}
@@ -0,0 +1,175 @@
+package introspection_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/introspection"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func loadSchema(t *testing.T) *ast.Schema {
+	t.Helper()
+	input, err := os.ReadFile("testdata/schema.graphql")
+	require.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: string(input)})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestFromSchema(t *testing.T) {
+	result := introspection.FromSchema(loadSchema(t))
+	s := result.Schema
+
+	require.Equal(t, "A test schema.", *s.Description)
+	require.Equal(t, "Query", s.QueryType.Name)
+	require.Equal(t, "Mutation", s.MutationType.Name)
+	require.Nil(t, s.SubscriptionType)
+
+	types := map[string]introspection.FullType{}
+	for _, typ := range s.Types {
+		types[typ.Name] = typ
+	}
+	require.Contains(t, types, "__Schema")
+	require.Contains(t, types, "String")
+
+	query := types["Query"]
+	require.Equal(t, "OBJECT", query.Kind)
+	require.Equal(t, []string{"node", "search", "users", "legacy"}, fieldNames(query.Fields))
+	require.Equal(t, []introspection.TypeRef{}, query.Interfaces)
+	require.Nil(t, query.PossibleTypes)
+	require.Nil(t, query.InputFields)
+	require.Nil(t, query.IsOneOf)
+
+	search := query.Fields[1]
+	require.Equal(t, `{status: ACTIVE, tags: ["a\tb", "c"]}`, *search.Args[0].DefaultValue)
+	require.Equal(t, "10", *search.Args[1].DefaultValue)
+	require.Equal(t, "NON_NULL", search.Type.Kind)
+	require.Equal(t, "LIST", search.Type.OfType.Kind)
+	require.Equal(t, "NON_NULL", search.Type.OfType.OfType.Kind)
+	require.Equal(t, "UNION", search.Type.OfType.OfType.OfType.Kind)
+	require.Equal(t, "SearchResult", *search.Type.OfType.OfType.OfType.Name)
+
+	require.True(t, query.Fields[2].IsDeprecated)
+	require.Equal(t, "Use `search` instead.", *query.Fields[2].DeprecationReason)
+	require.Equal(t, "No longer supported", *query.Fields[3].DeprecationReason)
+	require.False(t, query.Fields[0].IsDeprecated)
+	require.Nil(t, query.Fields[0].DeprecationReason)
+
+	force := types["Mutation"].Fields[0].Args[2]
+	require.True(t, force.IsDeprecated)
+	require.Equal(t, "Ignored.", *force.DeprecationReason)
+
+	require.Equal(t, []string{"User"}, refNames(types["Node"].PossibleTypes))
+	require.Equal(t, []string{"Node"}, refNames(types["Entity"].Interfaces))
+	require.Equal(t, []string{"Node", "Entity"}, refNames(types["User"].Interfaces))
+	require.Equal(t, []string{"User"}, refNames(types["SearchResult"].PossibleTypes))
+
+	status := types["Status"].EnumValues
+	require.Len(t, status, 2)
+	require.True(t, status[1].IsDeprecated)
+
+	require.False(t, *types["Filter"].IsOneOf)
+	require.True(t, types["Filter"].InputFields[1].IsDeprecated)
+	require.True(t, *types["UserBy"].IsOneOf)
+
+	require.Equal(
+		t,
+		"https://scalars.graphql.org/andimarek/date-time",
+		*types["DateTime"].SpecifiedByURL,
+	)
+	require.Nil(t, types["String"].SpecifiedByURL)
+
+	var cached introspection.Directive
+	for _, dir := range s.Directives {
+		if dir.Name == "cached" {
+			cached = dir
+		}
+	}
+	require.True(t, cached.IsRepeatable)
+	require.Equal(t, "Caches the field.", *cached.Description)
+	require.Equal(
+		t,
+		[]ast.DirectiveLocation{ast.LocationField, ast.LocationQuery},
+		cached.Locations,
+	)
+	require.Equal(t, "60", *cached.Args[0].DefaultValue)
+}
+
+func TestFromSchemaJSON(t *testing.T) {
+	data, err := json.Marshal(introspection.FromSchema(loadSchema(t)))
+	require.NoError(t, err)
+
+	var result struct {
+		Schema struct {
+			Types []map[string]any `json:"types"`
+		} `json:"__schema"`
+	}
+	require.NoError(t, json.Unmarshal(data, &result))
+	for _, typ := range result.Schema.Types {
+		if typ["name"] != "DateTime" {
+			continue
+		}
+		require.Equal(t, map[string]any{
+			"kind":           "SCALAR",
+			"name":           "DateTime",
+			"description":    nil,
+			"specifiedByURL": "https://scalars.graphql.org/andimarek/date-time",
+			"isOneOf":        nil,
+			"fields":         nil,
+			"inputFields":    nil,
+			"interfaces":     nil,
+			"enumValues":     nil,
+			"possibleTypes":  nil,
+		}, typ)
+		return
+	}
+	t.Fatal("DateTime is missing")
+}
+
+func TestFromSchemaRoundTrip(t *testing.T) {
+	schema := loadSchema(t)
+	data, err := json.Marshal(introspection.FromSchema(schema))
+	require.NoError(t, err)
+
+	client, err := validator.BuildClientSchema(data)
+	require.NoError(t, err)
+	// The default reason of @deprecated is given explicitly once it has been
+	// through introspection.
+	expected := strings.ReplaceAll(
+		formatSchema(schema),
+		"@deprecated\n",
+		`@deprecated(reason: "No longer supported")`+"\n",
+	)
+	require.Equal(t, expected, formatSchema(client))
+}
+
+func formatSchema(schema *ast.Schema) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatSchema(schema)
+	return buf.String()
+}
+
+func fieldNames(fields []introspection.Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+func refNames(refs []introspection.TypeRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, *ref.Name)
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// PrintValue prints value the way graphql-js does, which is spaced unlike
+// ast.Value.String. It is how default values are given in an introspection
+// result.
+func PrintValue(value *ast.Value) string {
+	var b strings.Builder
+	writeValue(&b, value)
+	return b.String()
+}
+
+func writeValue(b *strings.Builder, value *ast.Value) {
+	switch value.Kind {
+	case ast.Variable:
+		b.WriteString("$" + value.Raw)
+	case ast.StringValue, ast.BlockValue:
+		writeString(b, value.Raw)
+	case ast.ListValue:
+		b.WriteString("[")
+		for i, child := range value.Children {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeValue(b, child.Value)
+		}
+		b.WriteString("]")
+	case ast.ObjectValue:
+		b.WriteString("{")
+		for i, child := range value.Children {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(child.Name + ": ")
+			writeValue(b, child.Value)
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString(value.Raw)
+	}
+}
+
+// writeString writes s as a GraphQL string, escaping control characters
+// with the short escape sequences where the language has one.
+func writeString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+				fmt.Fprintf(b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
@@ -205,6 +205,17 @@ func (s *Lexer) ReadToken() (Token, error) {
 	return s.makeError(`Cannot parse the unexpected character "%s".`, string(r))
 }
 
+// Skip moves the lexer past the input that caused the last ReadToken error so
+// that lexing can resume. Errors that already consumed some input are left as is.
+func (s *Lexer) Skip() {
+	if s.end > s.start || s.end >= len(s.Input) {
+		return
+	}
+	_, w := s.peek()
+	s.end += w
+	s.endRunes++
+}
+
 // ws reads from body starting at startPosition until it finds a non-whitespace
 // or commented character, and updates the token end to include all whitespace.
 func (s *Lexer) ws() {
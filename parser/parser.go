@@ -8,10 +8,28 @@ import (
 	"github.com/vektah/gqlparser/v2/lexer"
 )
 
+// Options configures optional parser behaviour.
+type Options struct {
+	// MaxTokenLimit aborts parsing once more than this many tokens have been
+	// read. 0 means unlimited.
+	MaxTokenLimit int
+
+	// Recover makes the parser resynchronise after a syntax error instead of
+	// stopping at the first one. The returned document is a best effort: nodes
+	// that failed to parse are kept with whatever was read before the error, so
+	// a broken field may have an empty Name. Every syntax error found is
+	// reported, at most one per source location.
+	Recover bool
+}
+
 type parser struct {
 	lexer lexer.Lexer
 	err   error
 
+	recovering bool
+	errs       gqlerror.List
+	open       []lexer.Type // brackets opened and not yet closed, used to resync
+
 	peeked    bool
 	peekToken lexer.Token
 	peekError error
@@ -95,6 +113,11 @@ func (p *parser) error(tok lexer.Token, format string, args ...any) {
 	if p.err != nil {
 		return
 	}
+	if p.recovering && tok.Kind == lexer.Invalid && p.peekError != nil {
+		// the lexer knows more about what went wrong than "Unexpected <Invalid>"
+		p.err = p.peekError
+		return
+	}
 	p.err = gqlerror.ErrorLocf(tok.Pos.Src.Name, tok.Pos.Line, tok.Pos.Column, format, args...)
 }
 
@@ -118,9 +141,33 @@ func (p *parser) next() lexer.Token {
 			p.consumeCommentGroup()
 		}
 	}
+
+	switch p.prev.Kind {
+	case lexer.BraceL, lexer.BracketL, lexer.ParenL:
+		p.open = append(p.open, p.prev.Kind)
+	case lexer.BraceR:
+		p.close(lexer.BraceL)
+	case lexer.BracketR:
+		p.close(lexer.BracketL)
+	case lexer.ParenR:
+		p.close(lexer.ParenL)
+	}
 	return p.prev
 }
 
+// close pops the innermost open bracket of kind opener along with any left
+// unclosed inside it, so a missing "]" does not leave the parser nested one
+// level deeper for the rest of the input. A closer without an opener is
+// ignored.
+func (p *parser) close(opener lexer.Type) {
+	for i := len(p.open) - 1; i >= 0; i-- {
+		if p.open[i] == opener {
+			p.open = p.open[:i]
+			return
+		}
+	}
+}
+
 func (p *parser) expectKeyword(value string) (lexer.Token, *ast.CommentGroup) {
 	tok := p.peek()
 	comment := p.comment
@@ -171,9 +218,13 @@ func (p *parser) many(start, end lexer.Type, cb func()) {
 		return
 	}
 
-	for p.peek().Kind != end && p.err == nil {
+	for p.peek().Kind != end && p.err == nil && !p.atRecoveredEOF() {
 		cb()
 	}
+	if p.atRecoveredEOF() {
+		p.expect(end)
+		return
+	}
 	p.next()
 }
 
@@ -184,7 +235,7 @@ func (p *parser) some(start, end lexer.Type, cb func()) *ast.CommentGroup {
 	}
 
 	called := false
-	for p.peek().Kind != end && p.err == nil {
+	for p.peek().Kind != end && p.err == nil && !p.atRecoveredEOF() {
 		called = true
 		cb()
 	}
@@ -194,7 +245,94 @@ func (p *parser) some(start, end lexer.Type, cb func()) *ast.CommentGroup {
 		return nil
 	}
 
+	if p.atRecoveredEOF() {
+		p.expect(end)
+		return nil
+	}
+
 	comment := p.comment
 	p.next()
 	return comment
 }
+
+// atRecoveredEOF reports whether a recovering parser has run out of input
+// inside a list. Lists stop there instead of calling back forever, which
+// would only repeat the same error at EOF.
+func (p *parser) atRecoveredEOF() bool {
+	return p.recovering && p.err == nil && p.peek().Kind == lexer.EOF
+}
+
+// recoverError moves the pending error into p.errs so that parsing can carry
+// on. It returns false when the parser is not recovering or the error is fatal.
+func (p *parser) recoverError() bool {
+	if p.err == nil || !p.recovering {
+		return false
+	}
+	if p.maxTokenLimit != 0 && p.tokenCount > p.maxTokenLimit {
+		return false
+	}
+	p.addError(p.err)
+	p.err = nil
+	return true
+}
+
+// addError records err unless an error has already been reported at the same
+// location; a single mistake often trips every enclosing list at once.
+func (p *parser) addError(err error) {
+	gqlErr := gqlerror.WrapIfUnwrapped(err)
+	for _, existing := range p.errs {
+		if len(existing.Locations) > 0 && len(gqlErr.Locations) > 0 &&
+			existing.Locations[0] == gqlErr.Locations[0] {
+			return
+		}
+	}
+	p.errs = append(p.errs, gqlErr)
+}
+
+// errors returns every error found while parsing, including a pending one.
+func (p *parser) errors() gqlerror.List {
+	if p.err != nil {
+		p.addError(p.err)
+		p.err = nil
+	}
+	return p.errs
+}
+
+// syncPoint remembers where a node started, so that resync can skip whatever
+// is left of it after an error.
+type syncPoint struct {
+	start int // offset of the first token of the node
+	depth int // bracket nesting before the node
+}
+
+func (p *parser) syncPoint() syncPoint {
+	return syncPoint{start: p.peek().Pos.Start, depth: len(p.open)}
+}
+
+// resync recovers from the pending error and discards tokens until stop
+// accepts one at the nesting depth the failed node started at, or EOF is
+// reached. The first token of the failed node is never accepted so that the
+// parser always makes progress.
+func (p *parser) resync(from syncPoint, stop func(tok lexer.Token) bool) {
+	if !p.recoverError() {
+		return
+	}
+
+	for {
+		tok := p.peek()
+		if tok.Kind == lexer.EOF {
+			return
+		}
+		if len(p.open) <= from.depth && tok.Pos.Start != from.start && stop(tok) {
+			return
+		}
+		if tok.Kind == lexer.Invalid {
+			p.lexer.Skip()
+		}
+
+		p.next()
+		if p.err != nil && !p.recoverError() {
+			return
+		}
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	. "github.com/vektah/gqlparser/v2/ast" //nolint:staticcheck // bad, yeah
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/lexer"
 )
 
@@ -21,6 +22,33 @@ func ParseQueryWithTokenLimit(source *Source, maxTokenLimit int) (*QueryDocument
 	return p.parseQueryDocument(), p.err
 }
 
+// ParseQueryWithOptions parses a query document using the given options. With
+// opts.Recover set it keeps going after syntax errors and returns the partial
+// document together with all of them.
+func ParseQueryWithOptions(source *Source, opts Options) (*QueryDocument, gqlerror.List) {
+	p := parser{
+		lexer:         lexer.New(source),
+		maxTokenLimit: opts.MaxTokenLimit,
+		recovering:    opts.Recover,
+	}
+	doc := p.parseQueryDocument()
+	return doc, p.errors()
+}
+
+// ParseConstValue parses source as a single value literal, which may not refer
+// to variables, such as the default values of an introspection result.
+func ParseConstValue(source *Source) (*Value, error) {
+	p := parser{
+		lexer: lexer.New(source),
+	}
+	value := p.parseValueLiteral(true)
+	p.expect(lexer.EOF)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return value, nil
+}
+
 func (p *parser) parseQueryDocument() *QueryDocument {
 	var doc QueryDocument
 	for p.peek().Kind != lexer.EOF {
@@ -28,6 +56,7 @@ func (p *parser) parseQueryDocument() *QueryDocument {
 			return &doc
 		}
 		doc.Position = p.peekPos()
+		start := p.syncPoint()
 		switch p.peek().Kind {
 		case lexer.Name:
 			switch p.peek().Value {
@@ -43,11 +72,32 @@ func (p *parser) parseQueryDocument() *QueryDocument {
 		default:
 			p.unexpectedError()
 		}
+
+		if p.err != nil {
+			p.resync(start, isExecutableDefinitionStart)
+		}
 	}
 
 	return &doc
 }
 
+func isExecutableDefinitionStart(tok lexer.Token) bool {
+	switch tok.Kind {
+	case lexer.BraceL:
+		return true
+	case lexer.Name:
+		switch tok.Value {
+		case "query", "mutation", "subscription", "fragment":
+			return true
+		}
+	}
+	return false
+}
+
+func isSelectionStart(tok lexer.Token) bool {
+	return tok.Kind == lexer.Name || tok.Kind == lexer.Spread || tok.Kind == lexer.BraceR
+}
+
 func (p *parser) parseOperationDefinition() *OperationDefinition {
 	if p.peek().Kind == lexer.BraceL {
 		return &OperationDefinition{
@@ -59,6 +109,7 @@ func (p *parser) parseOperationDefinition() *OperationDefinition {
 	}
 
 	var od OperationDefinition
+	start := p.syncPoint()
 	od.Position = p.peekPos()
 	od.Comment = p.comment
 	od.Operation = p.parseOperationType()
@@ -69,6 +120,10 @@ func (p *parser) parseOperationDefinition() *OperationDefinition {
 
 	od.VariableDefinitions = p.parseVariableDefinitions()
 	od.Directives = p.parseDirectives(false)
+	if p.err != nil {
+		// a broken header should not cost us the selection set that follows it
+		p.resync(start, isExecutableDefinitionStart)
+	}
 	od.SelectionSet = p.parseRequiredSelectionSet()
 
 	return &od
@@ -122,12 +177,7 @@ func (p *parser) parseVariable() string {
 }
 
 func (p *parser) parseOptionalSelectionSet() SelectionSet {
-	var selections []Selection
-	p.some(lexer.BraceL, lexer.BraceR, func() {
-		selections = append(selections, p.parseSelection())
-	})
-
-	return selections
+	return p.parseSelections()
 }
 
 func (p *parser) parseRequiredSelectionSet() SelectionSet {
@@ -136,9 +186,17 @@ func (p *parser) parseRequiredSelectionSet() SelectionSet {
 		return nil
 	}
 
+	return p.parseSelections()
+}
+
+func (p *parser) parseSelections() SelectionSet {
 	var selections []Selection
 	p.some(lexer.BraceL, lexer.BraceR, func() {
+		start := p.syncPoint()
 		selections = append(selections, p.parseSelection())
+		if p.err != nil {
+			p.resync(start, isSelectionStart)
+		}
 	})
 
 	return selections
@@ -220,6 +278,7 @@ func (p *parser) parseFragment() Selection {
 
 func (p *parser) parseFragmentDefinition() *FragmentDefinition {
 	var def FragmentDefinition
+	start := p.syncPoint()
 	def.Position = p.peekPos()
 	def.Comment = p.comment
 	p.expectKeyword("fragment")
@@ -231,6 +290,9 @@ func (p *parser) parseFragmentDefinition() *FragmentDefinition {
 
 	def.TypeCondition = p.parseName()
 	def.Directives = p.parseDirectives(false)
+	if p.err != nil {
+		p.resync(start, isExecutableDefinitionStart)
+	}
 	def.SelectionSet = p.parseRequiredSelectionSet()
 	return &def
 }
@@ -367,6 +429,9 @@ func (p *parser) parseTypeReference() *Type {
 
 func (p *parser) parseName() string {
 	token, _ := p.expect(lexer.Name)
+	if token.Kind != lexer.Name {
+		return ""
+	}
 
 	return token.Value
 }
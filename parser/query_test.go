@@ -53,3 +53,121 @@ query SomeOperation {
 		)
 	})
 }
+
+func TestQueryRecovery(t *testing.T) {
+	parse := func(input string) (*ast.QueryDocument, []string) {
+		doc, errs := ParseQueryWithOptions(
+			&ast.Source{Input: input, Name: "spec"},
+			Options{Recover: true},
+		)
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return doc, messages
+	}
+
+	t.Run("reports every broken selection", func(t *testing.T) {
+		doc, errs := parse(`{ a(x: ) b c: }`)
+		assert.Equal(t, []string{
+			"spec:1:8: Unexpected )",
+			"spec:1:15: Expected Name, found }",
+		}, errs)
+
+		selections := doc.Operations[0].SelectionSet
+		assert.Len(t, selections, 3)
+		assert.Equal(t, "a", selections[0].(*ast.Field).Name)
+		assert.Equal(t, "b", selections[1].(*ast.Field).Name)
+		assert.Equal(t, "c", selections[2].(*ast.Field).Alias)
+		assert.Equal(t, "", selections[2].(*ast.Field).Name)
+	})
+
+	t.Run("resynchronises at definitions", func(t *testing.T) {
+		doc, errs := parse(`
+query Foo($x: ) { a }
+notanoperation { b }
+fragment F on T { ... }
+`)
+		assert.Equal(t, []string{
+			"spec:2:15: Expected Name, found )",
+			`spec:3:1: Unexpected Name "notanoperation"`,
+			"spec:4:23: Expected {, found }",
+		}, errs)
+
+		assert.Len(t, doc.Operations, 2)
+		assert.Equal(t, "Foo", doc.Operations[0].Name)
+		assert.Equal(t, "a", doc.Operations[0].SelectionSet[0].(*ast.Field).Name)
+		assert.Equal(t, "b", doc.Operations[1].SelectionSet[0].(*ast.Field).Name)
+		assert.Equal(t, "F", doc.Fragments.ForName("F").Name)
+	})
+
+	t.Run("resynchronises after an unclosed bracket in a header", func(t *testing.T) {
+		doc, errs := parse(`query A($v: [Int) { a } query B { b }`)
+		assert.Equal(t, []string{"spec:1:17: Expected ], found )"}, errs)
+
+		assert.Len(t, doc.Operations, 2)
+		assert.Equal(t, "a", doc.Operations.ForName("A").SelectionSet[0].(*ast.Field).Name)
+		assert.Equal(t, "b", doc.Operations.ForName("B").SelectionSet[0].(*ast.Field).Name)
+	})
+
+	t.Run("resynchronises after an unclosed bracket in a selection", func(t *testing.T) {
+		doc, errs := parse(`query A { a(x: [1) } query B { b }`)
+		assert.Equal(t, []string{"spec:1:18: Unexpected )"}, errs)
+
+		assert.Len(t, doc.Operations, 2)
+		assert.Len(t, doc.Operations.ForName("A").SelectionSet, 1)
+		assert.Equal(t, "a", doc.Operations.ForName("A").SelectionSet[0].(*ast.Field).Name)
+		assert.Equal(t, "b", doc.Operations.ForName("B").SelectionSet[0].(*ast.Field).Name)
+	})
+
+	t.Run("reports lexer errors and keeps going", func(t *testing.T) {
+		doc, errs := parse(`{ a ? b }`)
+		assert.Equal(t, []string{`spec:1:5: Cannot parse the unexpected character "?".`}, errs)
+		assert.Equal(t, "b", doc.Operations[0].SelectionSet[2].(*ast.Field).Name)
+	})
+
+	t.Run("reports unterminated documents once", func(t *testing.T) {
+		doc, errs := parse(`{ a { b(x: [1`)
+		assert.Equal(t, []string{"spec:1:14: Expected ], found <EOF>"}, errs)
+		a := doc.Operations[0].SelectionSet[0].(*ast.Field)
+		assert.Equal(t, "b", a.SelectionSet[0].(*ast.Field).Name)
+	})
+
+	t.Run("valid documents have no errors", func(t *testing.T) {
+		doc, errs := parse(`query A { a { b } } fragment F on T { c }`)
+		assert.Empty(t, errs)
+		assert.Len(t, doc.Operations, 1)
+		assert.Len(t, doc.Fragments, 1)
+	})
+
+	t.Run("stops at the token limit", func(t *testing.T) {
+		_, errs := ParseQueryWithOptions(
+			&ast.Source{Input: `{ a( ) b( ) c( ) d }`, Name: "spec"},
+			Options{Recover: true, MaxTokenLimit: 5},
+		)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "exceeded token limit of 5", errs[1].Message)
+	})
+
+	t.Run("without recovery only the first error is returned", func(t *testing.T) {
+		_, errs := ParseQueryWithOptions(&ast.Source{Input: `{ a( ) b( ) }`}, Options{})
+		assert.Len(t, errs, 1)
+	})
+}
+
+func TestParseConstValue(t *testing.T) {
+	value, err := ParseConstValue(&ast.Source{Input: `{a: [1, "b"], c: null}`})
+	assert.NoError(t, err)
+	assert.Equal(t, `{a:[1,"b"],c:null}`, value.String())
+
+	for input, message := range map[string]string{
+		"":             "Unexpected <EOF>",
+		"$var":         "Unexpected $",
+		"1 2":          "Expected <EOF>, found Int",
+		"1): Int\n  x": "Expected <EOF>, found )",
+		"{a: $b}":      "Unexpected $",
+	} {
+		_, err := ParseConstValue(&ast.Source{Name: "value", Input: input})
+		assert.ErrorContains(t, err, message, input)
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	. "github.com/vektah/gqlparser/v2/ast" //nolint:staticcheck // bad, yeah
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/lexer"
 )
 
@@ -69,6 +70,48 @@ func ParseSchemaWithLimit(source *Source, maxTokenLimit int) (*SchemaDocument, e
 	return sd, nil
 }
 
+// ParseSchemasWithOptions parses and merges several schema sources using the
+// given options. With opts.Recover set every source is parsed to the end and
+// the errors of all of them are returned together.
+func ParseSchemasWithOptions(opts Options, inputs ...*Source) (*SchemaDocument, gqlerror.List) {
+	sd := &SchemaDocument{}
+	var errs gqlerror.List
+	for _, input := range inputs {
+		inputAst, inputErrs := ParseSchemaWithOptions(input, opts)
+		errs = append(errs, inputErrs...)
+		if len(inputErrs) > 0 && !opts.Recover {
+			return nil, errs
+		}
+		sd.Merge(inputAst)
+	}
+	return sd, errs
+}
+
+// ParseSchemaWithOptions parses a schema document using the given options. With
+// opts.Recover set it skips ahead to the next top level definition after a
+// syntax error and returns the partial document together with all errors.
+func ParseSchemaWithOptions(source *Source, opts Options) (*SchemaDocument, gqlerror.List) {
+	p := parser{
+		lexer:         lexer.New(source),
+		maxTokenLimit: opts.MaxTokenLimit,
+		recovering:    opts.Recover,
+	}
+	sd := p.parseSchemaDocument()
+	errs := p.errors()
+	if sd == nil {
+		return nil, errs
+	}
+
+	for _, def := range sd.Definitions {
+		def.BuiltIn = source.BuiltIn
+	}
+	for _, def := range sd.Extensions {
+		def.BuiltIn = source.BuiltIn
+	}
+
+	return sd, errs
+}
+
 func (p *parser) parseSchemaDocument() *SchemaDocument {
 	var doc SchemaDocument
 	doc.Position = p.peekPos()
@@ -76,6 +119,7 @@ func (p *parser) parseSchemaDocument() *SchemaDocument {
 		if p.err != nil {
 			return nil
 		}
+		start := p.syncPoint()
 
 		var description descriptionWithComment
 		if p.peek().Kind == lexer.BlockString || p.peek().Kind == lexer.String {
@@ -84,24 +128,29 @@ func (p *parser) parseSchemaDocument() *SchemaDocument {
 
 		if p.peek().Kind != lexer.Name {
 			p.unexpectedError()
-			break
+		} else {
+			switch p.peek().Value {
+			case "scalar", "type", "interface", "union", "enum", "input":
+				doc.Definitions = append(
+					doc.Definitions,
+					p.parseTypeSystemDefinition(description),
+				)
+			case "schema":
+				doc.Schema = append(doc.Schema, p.parseSchemaDefinition(description))
+			case "directive":
+				doc.Directives = append(doc.Directives, p.parseDirectiveDefinition(description))
+			case "extend":
+				if description.text != "" {
+					p.unexpectedToken(p.prev)
+				}
+				p.parseTypeSystemExtension(&doc)
+			default:
+				p.unexpectedError()
+			}
 		}
 
-		switch p.peek().Value {
-		case "scalar", "type", "interface", "union", "enum", "input":
-			doc.Definitions = append(doc.Definitions, p.parseTypeSystemDefinition(description))
-		case "schema":
-			doc.Schema = append(doc.Schema, p.parseSchemaDefinition(description))
-		case "directive":
-			doc.Directives = append(doc.Directives, p.parseDirectiveDefinition(description))
-		case "extend":
-			if description.text != "" {
-				p.unexpectedToken(p.prev)
-			}
-			p.parseTypeSystemExtension(&doc)
-		default:
-			p.unexpectedError()
-			return nil
+		if p.err != nil {
+			p.resync(start, p.atTypeSystemDefinitionStart)
 		}
 	}
 
@@ -111,6 +160,27 @@ func (p *parser) parseSchemaDocument() *SchemaDocument {
 	return &doc
 }
 
+// atTypeSystemDefinitionStart matches the keyword or description that starts
+// a top level definition, or anything right after a definition body has been
+// closed. It is only consulted outside of any brackets, where a string can be
+// nothing but a description.
+func (p *parser) atTypeSystemDefinitionStart(tok lexer.Token) bool {
+	if p.prev.Kind == lexer.BraceR {
+		return true
+	}
+	switch tok.Kind {
+	case lexer.String, lexer.BlockString:
+		return true
+	case lexer.Name:
+		switch tok.Value {
+		case "scalar", "type", "interface", "union", "enum", "input", "schema", "directive",
+			"extend":
+			return true
+		}
+	}
+	return false
+}
+
 func (p *parser) parseDescription() descriptionWithComment {
 	token := p.peek()
 
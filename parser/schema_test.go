@@ -141,3 +141,117 @@ func TestTypePosition(t *testing.T) {
 		)
 	})
 }
+
+func TestSchemaRecovery(t *testing.T) {
+	parse := func(input string) (*ast.SchemaDocument, []string) {
+		doc, errs := ParseSchemaWithOptions(
+			&ast.Source{Input: input, Name: "spec"},
+			Options{Recover: true},
+		)
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return doc, messages
+	}
+
+	t.Run("reports every broken definition", func(t *testing.T) {
+		doc, errs := parse(`
+type Query {
+  a: String
+  b(: Int): Int
+  type: Int
+}
+
+inptu Foo { a: Int }
+
+enum Color { RED GREEN 1 BLUE }
+
+"description"
+directive @d on FIELD | NOPE
+
+"""
+description
+"""
+type Valid {
+  a: String
+}
+
+extend type Query { d: Int }
+`)
+		assert.Equal(t, []string{
+			"spec:4:5: Expected Name, found :",
+			`spec:8:1: Unexpected Name "inptu"`,
+			"spec:10:24: Expected Name, found Int",
+			`spec:13:25: Unexpected Name "NOPE"`,
+		}, errs)
+
+		query := doc.Definitions.ForName("Query")
+		assert.Equal(t, []string{"a", "b"}, fieldNames(query.Fields))
+		assert.Nil(t, doc.Definitions.ForName("Foo"))
+		assert.Len(t, doc.Definitions.ForName("Color").EnumValues, 3)
+		assert.Equal(t, "description", doc.Directives.ForName("d").Description)
+		assert.Equal(t, "description", doc.Definitions.ForName("Valid").Description)
+		assert.Equal(t, []string{"a"}, fieldNames(doc.Definitions.ForName("Valid").Fields))
+		assert.Equal(t, []string{"d"}, fieldNames(doc.Extensions.ForName("Query").Fields))
+	})
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "unclosed [",
+			input: `type A { a: Int } type B { b: [Int } type C { c: Int }`,
+			err:   "spec:1:36: Expected ], found }",
+		},
+		{
+			name:  "unclosed (",
+			input: `type A { a: Int } type B { b(x: Int: Int } type C { c: Int }`,
+			err:   "spec:1:36: Expected Name, found :",
+		},
+		{
+			name:  "unclosed {",
+			input: `type A { a: Int } type B { b(x: I = { i: 1 ): Int } type C { c: Int }`,
+			err:   "spec:1:44: Expected Name, found )",
+		},
+	} {
+		t.Run("resynchronises after an "+tc.name, func(t *testing.T) {
+			doc, errs := parse(tc.input)
+			assert.Equal(t, []string{tc.err}, errs)
+			assert.Equal(t, []string{"a"}, fieldNames(doc.Definitions.ForName("A").Fields))
+			assert.Equal(t, []string{"c"}, fieldNames(doc.Definitions.ForName("C").Fields))
+		})
+	}
+
+	t.Run("keeps builtin flag", func(t *testing.T) {
+		doc, errs := ParseSchemaWithOptions(
+			&ast.Source{Input: "scalar Foo type Bar { a: }", BuiltIn: true},
+			Options{Recover: true},
+		)
+		assert.Len(t, errs, 1)
+		assert.True(t, doc.Definitions.ForName("Foo").BuiltIn)
+		assert.True(t, doc.Definitions.ForName("Bar").BuiltIn)
+	})
+
+	t.Run("merges errors across sources", func(t *testing.T) {
+		doc, errs := ParseSchemasWithOptions(
+			Options{Recover: true},
+			&ast.Source{Name: "a", Input: "type A { a: }"},
+			&ast.Source{Name: "b", Input: "type B { b: Int } !"},
+		)
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "a", errs[0].Extensions["file"])
+		assert.Equal(t, "b", errs[1].Extensions["file"])
+		assert.Len(t, doc.Definitions, 2)
+	})
+}
+
+func fieldNames(fields ast.FieldList) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
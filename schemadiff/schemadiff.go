@@ -0,0 +1,749 @@
+// Package schemadiff compares two schemas and reports what changed between
+// them, classifying each change by whether it can break existing clients.
+// It follows findBreakingChanges and findDangerousChanges of graphql-js, and
+// also reports the additions those leave out as safe changes.
+package schemadiff
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type Criticality int
+
+const (
+	// Safe changes do not affect existing clients.
+	Safe Criticality = iota
+	// Dangerous changes keep existing queries valid but may change how they
+	// behave, such as a new enum value a client does not know how to handle.
+	Dangerous
+	// Breaking changes can make existing queries invalid or fail at runtime.
+	Breaking
+)
+
+func (c Criticality) String() string {
+	switch c {
+	case Safe:
+		return "SAFE"
+	case Dangerous:
+		return "DANGEROUS"
+	case Breaking:
+		return "BREAKING"
+	default:
+		return fmt.Sprintf("Criticality(%d)", int(c))
+	}
+}
+
+type ChangeType string
+
+const (
+	TypeRemoved                 ChangeType = "TYPE_REMOVED"
+	TypeChangedKind             ChangeType = "TYPE_CHANGED_KIND"
+	TypeRemovedFromUnion        ChangeType = "TYPE_REMOVED_FROM_UNION"
+	ValueRemovedFromEnum        ChangeType = "VALUE_REMOVED_FROM_ENUM"
+	RequiredInputFieldAdded     ChangeType = "REQUIRED_INPUT_FIELD_ADDED"
+	ImplementedInterfaceRemoved ChangeType = "IMPLEMENTED_INTERFACE_REMOVED"
+	FieldRemoved                ChangeType = "FIELD_REMOVED"
+	FieldChangedKind            ChangeType = "FIELD_CHANGED_KIND"
+	RequiredArgAdded            ChangeType = "REQUIRED_ARG_ADDED"
+	ArgRemoved                  ChangeType = "ARG_REMOVED"
+	ArgChangedKind              ChangeType = "ARG_CHANGED_KIND"
+	DirectiveRemoved            ChangeType = "DIRECTIVE_REMOVED"
+	DirectiveArgRemoved         ChangeType = "DIRECTIVE_ARG_REMOVED"
+	RequiredDirectiveArgAdded   ChangeType = "REQUIRED_DIRECTIVE_ARG_ADDED"
+	DirectiveRepeatableRemoved  ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+	DirectiveLocationRemoved    ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+
+	ValueAddedToEnum        ChangeType = "VALUE_ADDED_TO_ENUM"
+	InterfaceAddedToObject  ChangeType = "INTERFACE_ADDED_TO_OBJECT"
+	TypeAddedToUnion        ChangeType = "TYPE_ADDED_TO_UNION"
+	OptionalInputFieldAdded ChangeType = "OPTIONAL_INPUT_FIELD_ADDED"
+	OptionalArgAdded        ChangeType = "OPTIONAL_ARG_ADDED"
+	ArgDefaultValueChange   ChangeType = "ARG_DEFAULT_VALUE_CHANGE"
+
+	TypeAdded                 ChangeType = "TYPE_ADDED"
+	FieldAdded                ChangeType = "FIELD_ADDED"
+	FieldTypeChanged          ChangeType = "FIELD_TYPE_CHANGED"
+	ArgTypeChanged            ChangeType = "ARG_TYPE_CHANGED"
+	DirectiveAdded            ChangeType = "DIRECTIVE_ADDED"
+	OptionalDirectiveArgAdded ChangeType = "OPTIONAL_DIRECTIVE_ARG_ADDED"
+	DirectiveRepeatableAdded  ChangeType = "DIRECTIVE_REPEATABLE_ADDED"
+	DirectiveLocationAdded    ChangeType = "DIRECTIVE_LOCATION_ADDED"
+)
+
+var criticalities = map[ChangeType]Criticality{
+	TypeRemoved:                 Breaking,
+	TypeChangedKind:             Breaking,
+	TypeRemovedFromUnion:        Breaking,
+	ValueRemovedFromEnum:        Breaking,
+	RequiredInputFieldAdded:     Breaking,
+	ImplementedInterfaceRemoved: Breaking,
+	FieldRemoved:                Breaking,
+	FieldChangedKind:            Breaking,
+	RequiredArgAdded:            Breaking,
+	ArgRemoved:                  Breaking,
+	ArgChangedKind:              Breaking,
+	DirectiveRemoved:            Breaking,
+	DirectiveArgRemoved:         Breaking,
+	RequiredDirectiveArgAdded:   Breaking,
+	DirectiveRepeatableRemoved:  Breaking,
+	DirectiveLocationRemoved:    Breaking,
+
+	ValueAddedToEnum:        Dangerous,
+	InterfaceAddedToObject:  Dangerous,
+	TypeAddedToUnion:        Dangerous,
+	OptionalInputFieldAdded: Dangerous,
+	OptionalArgAdded:        Dangerous,
+	ArgDefaultValueChange:   Dangerous,
+
+	TypeAdded:                 Safe,
+	FieldAdded:                Safe,
+	FieldTypeChanged:          Safe,
+	ArgTypeChanged:            Safe,
+	DirectiveAdded:            Safe,
+	OptionalDirectiveArgAdded: Safe,
+	DirectiveRepeatableAdded:  Safe,
+	DirectiveLocationAdded:    Safe,
+}
+
+// Criticality returns how likely a change of type t is to affect clients.
+func (t ChangeType) Criticality() Criticality {
+	return criticalities[t]
+}
+
+// Change is a single difference between two schemas.
+type Change struct {
+	Type        ChangeType
+	Criticality Criticality
+	// Coordinate is the schema coordinate of what changed, such as
+	// "User.name(format:)" or "@cached(ttl:)".
+	Coordinate  string
+	Description string
+
+	// OldPosition and NewPosition locate the change in each schema. When
+	// something was added or removed, the side without it points at the
+	// closest parent that is in both, or is nil when there is none.
+	OldPosition *ast.Position `dump:"-" json:"-"`
+	NewPosition *ast.Position `dump:"-" json:"-"`
+}
+
+// Diff returns every change from oldSchema to newSchema. Types and directives
+// are compared in name order, then each of their members in the order they
+// were defined.
+func Diff(oldSchema, newSchema *ast.Schema) []*Change {
+	d := differ{}
+	d.diffTypes(oldSchema, newSchema)
+	d.diffDirectives(oldSchema, newSchema)
+	return d.changes
+}
+
+// FindBreakingChanges returns the changes from oldSchema to newSchema that
+// are breaking.
+func FindBreakingChanges(oldSchema, newSchema *ast.Schema) []*Change {
+	return filter(Diff(oldSchema, newSchema), Breaking)
+}
+
+// FindDangerousChanges returns the changes from oldSchema to newSchema that
+// are dangerous.
+func FindDangerousChanges(oldSchema, newSchema *ast.Schema) []*Change {
+	return filter(Diff(oldSchema, newSchema), Dangerous)
+}
+
+func filter(changes []*Change, criticality Criticality) []*Change {
+	return slices.DeleteFunc(changes, func(change *Change) bool {
+		return change.Criticality != criticality
+	})
+}
+
+type differ struct {
+	changes []*Change
+}
+
+func (d *differ) add(
+	typ ChangeType,
+	coordinate string,
+	oldPosition, newPosition *ast.Position,
+	format string,
+	args ...any,
+) {
+	d.changes = append(d.changes, &Change{
+		Type:        typ,
+		Criticality: typ.Criticality(),
+		Coordinate:  coordinate,
+		Description: fmt.Sprintf(format, args...),
+		OldPosition: oldPosition,
+		NewPosition: newPosition,
+	})
+}
+
+func (d *differ) diffTypes(oldSchema, newSchema *ast.Schema) {
+	for _, name := range sortedKeys(oldSchema.Types) {
+		oldType := oldSchema.Types[name]
+		newType := newSchema.Types[name]
+		if newType == nil {
+			d.add(TypeRemoved, name, oldType.Position, nil, "%s was removed.", name)
+			continue
+		}
+		if oldType.Kind != newType.Kind {
+			d.add(
+				TypeChangedKind,
+				name,
+				oldType.Position,
+				newType.Position,
+				"%s changed from %s to %s.",
+				name,
+				typeKindName(oldType.Kind),
+				typeKindName(newType.Kind),
+			)
+			continue
+		}
+
+		switch oldType.Kind {
+		case ast.Enum:
+			d.diffEnumValues(oldType, newType)
+		case ast.Union:
+			d.diffUnionMembers(oldType, newType)
+		case ast.InputObject:
+			d.diffInputFields(oldType, newType)
+		case ast.Object, ast.Interface:
+			d.diffFields(oldType, newType)
+			d.diffImplementedInterfaces(oldType, newType)
+		}
+	}
+
+	for _, name := range sortedKeys(newSchema.Types) {
+		if oldSchema.Types[name] == nil {
+			newType := newSchema.Types[name]
+			d.add(TypeAdded, name, nil, newType.Position, "%s was added.", name)
+		}
+	}
+}
+
+func (d *differ) diffEnumValues(oldType, newType *ast.Definition) {
+	for _, oldValue := range oldType.EnumValues {
+		if newType.EnumValues.ForName(oldValue.Name) == nil {
+			d.add(
+				ValueRemovedFromEnum,
+				oldType.Name+"."+oldValue.Name,
+				oldValue.Position,
+				newType.Position,
+				"%s was removed from enum type %s.",
+				oldValue.Name,
+				oldType.Name,
+			)
+		}
+	}
+	for _, newValue := range newType.EnumValues {
+		if oldType.EnumValues.ForName(newValue.Name) == nil {
+			d.add(
+				ValueAddedToEnum,
+				newType.Name+"."+newValue.Name,
+				oldType.Position,
+				newValue.Position,
+				"%s was added to enum type %s.",
+				newValue.Name,
+				newType.Name,
+			)
+		}
+	}
+}
+
+func (d *differ) diffUnionMembers(oldType, newType *ast.Definition) {
+	for i, member := range oldType.Types {
+		if !slices.Contains(newType.Types, member) {
+			d.add(
+				TypeRemovedFromUnion,
+				oldType.Name,
+				memberPosition(oldType, i),
+				newType.Position,
+				"%s was removed from union type %s.",
+				member,
+				oldType.Name,
+			)
+		}
+	}
+	for i, member := range newType.Types {
+		if !slices.Contains(oldType.Types, member) {
+			d.add(
+				TypeAddedToUnion,
+				newType.Name,
+				oldType.Position,
+				memberPosition(newType, i),
+				"%s was added to union type %s.",
+				member,
+				newType.Name,
+			)
+		}
+	}
+}
+
+func (d *differ) diffInputFields(oldType, newType *ast.Definition) {
+	for _, oldField := range oldType.Fields {
+		coordinate := oldType.Name + "." + oldField.Name
+		newField := newType.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(
+				FieldRemoved,
+				coordinate,
+				oldField.Position,
+				newType.Position,
+				"%s was removed.",
+				coordinate,
+			)
+			continue
+		}
+		if oldField.Type.String() == newField.Type.String() {
+			continue
+		}
+		changeType := FieldTypeChanged
+		if !isSafeInputTypeChange(oldField.Type, newField.Type) {
+			changeType = FieldChangedKind
+		}
+		d.add(
+			changeType,
+			coordinate,
+			oldField.Position,
+			newField.Position,
+			"%s changed type from %s to %s.",
+			coordinate,
+			oldField.Type,
+			newField.Type,
+		)
+	}
+
+	for _, newField := range newType.Fields {
+		if oldType.Fields.ForName(newField.Name) != nil {
+			continue
+		}
+		coordinate := newType.Name + "." + newField.Name
+		if isRequired(newField.Type, newField.DefaultValue) {
+			d.add(
+				RequiredInputFieldAdded,
+				coordinate,
+				oldType.Position,
+				newField.Position,
+				"A required field %s on input type %s was added.",
+				newField.Name,
+				newType.Name,
+			)
+		} else {
+			d.add(
+				OptionalInputFieldAdded,
+				coordinate,
+				oldType.Position,
+				newField.Position,
+				"An optional field %s on input type %s was added.",
+				newField.Name,
+				newType.Name,
+			)
+		}
+	}
+}
+
+func (d *differ) diffFields(oldType, newType *ast.Definition) {
+	for _, oldField := range oldType.Fields {
+		if strings.HasPrefix(oldField.Name, "__") {
+			continue
+		}
+		coordinate := oldType.Name + "." + oldField.Name
+		newField := newType.Fields.ForName(oldField.Name)
+		if newField == nil {
+			d.add(
+				FieldRemoved,
+				coordinate,
+				oldField.Position,
+				newType.Position,
+				"%s was removed.",
+				coordinate,
+			)
+			continue
+		}
+
+		d.diffArguments(coordinate, oldField, newField)
+
+		if oldField.Type.String() == newField.Type.String() {
+			continue
+		}
+		changeType := FieldTypeChanged
+		if !isSafeOutputTypeChange(oldField.Type, newField.Type) {
+			changeType = FieldChangedKind
+		}
+		d.add(
+			changeType,
+			coordinate,
+			oldField.Position,
+			newField.Position,
+			"%s changed type from %s to %s.",
+			coordinate,
+			oldField.Type,
+			newField.Type,
+		)
+	}
+
+	for _, newField := range newType.Fields {
+		if strings.HasPrefix(newField.Name, "__") || oldType.Fields.ForName(newField.Name) != nil {
+			continue
+		}
+		coordinate := newType.Name + "." + newField.Name
+		d.add(
+			FieldAdded,
+			coordinate,
+			oldType.Position,
+			newField.Position,
+			"%s was added.",
+			coordinate,
+		)
+	}
+}
+
+func (d *differ) diffArguments(field string, oldField, newField *ast.FieldDefinition) {
+	for _, oldArg := range oldField.Arguments {
+		coordinate := field + "(" + oldArg.Name + ":)"
+		newArg := newField.Arguments.ForName(oldArg.Name)
+		if newArg == nil {
+			d.add(
+				ArgRemoved,
+				coordinate,
+				oldArg.Position,
+				newField.Position,
+				"%s arg %s was removed.",
+				field,
+				oldArg.Name,
+			)
+			continue
+		}
+
+		if oldArg.Type.String() != newArg.Type.String() {
+			changeType := ArgTypeChanged
+			if !isSafeInputTypeChange(oldArg.Type, newArg.Type) {
+				changeType = ArgChangedKind
+			}
+			d.add(
+				changeType,
+				coordinate,
+				oldArg.Position,
+				newArg.Position,
+				"%s arg %s has changed type from %s to %s.",
+				field,
+				oldArg.Name,
+				oldArg.Type,
+				newArg.Type,
+			)
+		}
+
+		switch {
+		case oldArg.DefaultValue == nil:
+		case newArg.DefaultValue == nil:
+			d.add(
+				ArgDefaultValueChange,
+				coordinate,
+				oldArg.Position,
+				newArg.Position,
+				"%s arg %s defaultValue was removed.",
+				field,
+				oldArg.Name,
+			)
+		default:
+			oldValue := stringifyValue(oldArg.DefaultValue)
+			newValue := stringifyValue(newArg.DefaultValue)
+			if oldValue != newValue {
+				d.add(
+					ArgDefaultValueChange,
+					coordinate,
+					oldArg.Position,
+					newArg.Position,
+					"%s arg %s has changed defaultValue from %s to %s.",
+					field,
+					oldArg.Name,
+					oldValue,
+					newValue,
+				)
+			}
+		}
+	}
+
+	for _, newArg := range newField.Arguments {
+		if oldField.Arguments.ForName(newArg.Name) != nil {
+			continue
+		}
+		coordinate := field + "(" + newArg.Name + ":)"
+		if isRequired(newArg.Type, newArg.DefaultValue) {
+			d.add(
+				RequiredArgAdded,
+				coordinate,
+				oldField.Position,
+				newArg.Position,
+				"A required arg %s on %s was added.",
+				newArg.Name,
+				field,
+			)
+		} else {
+			d.add(
+				OptionalArgAdded,
+				coordinate,
+				oldField.Position,
+				newArg.Position,
+				"An optional arg %s on %s was added.",
+				newArg.Name,
+				field,
+			)
+		}
+	}
+}
+
+func (d *differ) diffImplementedInterfaces(oldType, newType *ast.Definition) {
+	for _, intf := range newType.Interfaces {
+		if !slices.Contains(oldType.Interfaces, intf) {
+			d.add(
+				InterfaceAddedToObject,
+				newType.Name,
+				oldType.Position,
+				newType.Position,
+				"%s added to interfaces implemented by %s.",
+				intf,
+				newType.Name,
+			)
+		}
+	}
+	for _, intf := range oldType.Interfaces {
+		if !slices.Contains(newType.Interfaces, intf) {
+			d.add(
+				ImplementedInterfaceRemoved,
+				oldType.Name,
+				oldType.Position,
+				newType.Position,
+				"%s no longer implements interface %s.",
+				oldType.Name,
+				intf,
+			)
+		}
+	}
+}
+
+func (d *differ) diffDirectives(oldSchema, newSchema *ast.Schema) {
+	for _, name := range sortedKeys(oldSchema.Directives) {
+		oldDirective := oldSchema.Directives[name]
+		newDirective := newSchema.Directives[name]
+		coordinate := "@" + name
+		if newDirective == nil {
+			d.add(
+				DirectiveRemoved,
+				coordinate,
+				oldDirective.Position,
+				nil,
+				"%s was removed.",
+				coordinate,
+			)
+			continue
+		}
+
+		for _, oldArg := range oldDirective.Arguments {
+			if newDirective.Arguments.ForName(oldArg.Name) == nil {
+				d.add(
+					DirectiveArgRemoved,
+					coordinate+"("+oldArg.Name+":)",
+					oldArg.Position,
+					newDirective.Position,
+					"%s was removed from %s.",
+					oldArg.Name,
+					coordinate,
+				)
+			}
+		}
+		for _, newArg := range newDirective.Arguments {
+			if oldDirective.Arguments.ForName(newArg.Name) != nil {
+				continue
+			}
+			if isRequired(newArg.Type, newArg.DefaultValue) {
+				d.add(
+					RequiredDirectiveArgAdded,
+					coordinate+"("+newArg.Name+":)",
+					oldDirective.Position,
+					newArg.Position,
+					"A required arg %s on directive %s was added.",
+					newArg.Name,
+					coordinate,
+				)
+			} else {
+				d.add(
+					OptionalDirectiveArgAdded,
+					coordinate+"("+newArg.Name+":)",
+					oldDirective.Position,
+					newArg.Position,
+					"An optional arg %s on directive %s was added.",
+					newArg.Name,
+					coordinate,
+				)
+			}
+		}
+
+		if oldDirective.IsRepeatable && !newDirective.IsRepeatable {
+			d.add(
+				DirectiveRepeatableRemoved,
+				coordinate,
+				oldDirective.Position,
+				newDirective.Position,
+				"Repeatable flag was removed from %s.",
+				coordinate,
+			)
+		} else if !oldDirective.IsRepeatable && newDirective.IsRepeatable {
+			d.add(
+				DirectiveRepeatableAdded,
+				coordinate,
+				oldDirective.Position,
+				newDirective.Position,
+				"Repeatable flag was added to %s.",
+				coordinate,
+			)
+		}
+
+		for _, location := range oldDirective.Locations {
+			if !slices.Contains(newDirective.Locations, location) {
+				d.add(
+					DirectiveLocationRemoved,
+					coordinate,
+					oldDirective.Position,
+					newDirective.Position,
+					"%s was removed from %s.",
+					location,
+					coordinate,
+				)
+			}
+		}
+		for _, location := range newDirective.Locations {
+			if !slices.Contains(oldDirective.Locations, location) {
+				d.add(
+					DirectiveLocationAdded,
+					coordinate,
+					oldDirective.Position,
+					newDirective.Position,
+					"%s was added to %s.",
+					location,
+					coordinate,
+				)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(newSchema.Directives) {
+		if oldSchema.Directives[name] == nil {
+			d.add(
+				DirectiveAdded,
+				"@"+name,
+				nil,
+				newSchema.Directives[name].Position,
+				"@%s was added.",
+				name,
+			)
+		}
+	}
+}
+
+// isSafeOutputTypeChange reports whether a field of type oldType can return
+// newType instead without breaking clients, which is when newType is the same
+// or only adds non-null.
+func isSafeOutputTypeChange(oldType, newType *ast.Type) bool {
+	switch {
+	case oldType.NonNull:
+		return newType.NonNull && isSafeOutputTypeChange(nullable(oldType), nullable(newType))
+	case oldType.Elem != nil:
+		return (newType.Elem != nil && !newType.NonNull &&
+			isSafeOutputTypeChange(oldType.Elem, newType.Elem)) ||
+			(newType.NonNull && isSafeOutputTypeChange(oldType, nullable(newType)))
+	default:
+		return (isNamed(newType) && oldType.NamedType == newType.NamedType) ||
+			(newType.NonNull && isSafeOutputTypeChange(oldType, nullable(newType)))
+	}
+}
+
+// isSafeInputTypeChange reports whether an argument or input field of type
+// oldType can accept newType instead without breaking clients, which is when
+// newType is the same or only removes non-null.
+func isSafeInputTypeChange(oldType, newType *ast.Type) bool {
+	switch {
+	case oldType.NonNull:
+		if newType.NonNull {
+			return isSafeInputTypeChange(nullable(oldType), nullable(newType))
+		}
+		return isSafeInputTypeChange(nullable(oldType), newType)
+	case oldType.Elem != nil:
+		return newType.Elem != nil && !newType.NonNull &&
+			isSafeInputTypeChange(oldType.Elem, newType.Elem)
+	default:
+		return isNamed(newType) && oldType.NamedType == newType.NamedType
+	}
+}
+
+// isNamed reports whether t is a nullable named type, not wrapped in a list.
+func isNamed(t *ast.Type) bool {
+	return t.Elem == nil && !t.NonNull
+}
+
+func nullable(t *ast.Type) *ast.Type {
+	return &ast.Type{NamedType: t.NamedType, Elem: t.Elem, Position: t.Position}
+}
+
+func isRequired(t *ast.Type, defaultValue *ast.Value) bool {
+	return t.NonNull && defaultValue == nil
+}
+
+func typeKindName(kind ast.DefinitionKind) string {
+	switch kind {
+	case ast.Scalar:
+		return "a Scalar type"
+	case ast.Object:
+		return "an Object type"
+	case ast.Interface:
+		return "an Interface type"
+	case ast.Union:
+		return "a Union type"
+	case ast.Enum:
+		return "an Enum type"
+	case ast.InputObject:
+		return "an Input type"
+	default:
+		return string(kind)
+	}
+}
+
+func memberPosition(def *ast.Definition, i int) *ast.Position {
+	if i < len(def.TypePositions) {
+		return def.TypePositions[i]
+	}
+	return def.Position
+}
+
+// stringifyValue prints value with the fields of objects sorted, so default
+// values written in a different order compare equal.
+func stringifyValue(value *ast.Value) string {
+	switch value.Kind {
+	case ast.ListValue:
+		items := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			items = append(items, stringifyValue(child.Value))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ast.ObjectValue:
+		fields := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			fields = append(fields, child.Name+": "+stringifyValue(child.Value))
+		}
+		sort.Strings(fields)
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return value.String()
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,203 @@
+package schemadiff_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/schemadiff"
+)
+
+const oldSDL = `
+type Query {
+	user(id: ID!, first: Int = 10, after: String, format: String = "short"): User
+	users(filter: Filter): [User]
+	legacy: String
+	count: Int
+}
+
+interface Node { id: ID! }
+
+type User implements Node {
+	id: ID!
+	name: String
+	tags: [String]
+	friends: [User!]!
+}
+
+union Result = User | Post
+
+type Post { id: ID! }
+
+enum Status { ACTIVE BANNED }
+
+input Filter {
+	status: Status
+	limit: Int!
+	offset: Int
+}
+
+scalar Date
+
+directive @cached(ttl: Int, scope: String) repeatable on FIELD | QUERY
+directive @legacy on FIELD
+`
+
+const newSDL = `
+type Query {
+	user(id: ID, first: Int = 20, after: Int, sort: String, page: Int!): User
+	users(filter: Filter): [User!]
+	count: String
+	search: [Result]
+}
+
+interface Node { id: ID! }
+
+interface Named { name: String }
+
+type User implements Named {
+	id: ID!
+	name: String!
+	tags: String
+	friends: [User]
+}
+
+union Result = User | Comment
+
+type Post { id: ID! }
+
+type Comment { id: ID! }
+
+enum Status { ACTIVE PENDING }
+
+input Filter {
+	status: Status!
+	limit: Int
+	sort: String!
+	page: Int! = 1
+}
+
+type Date { value: String }
+
+directive @cached(ttl: Int, max: Int!, lazy: Boolean) on FIELD | SUBSCRIPTION
+directive @live on QUERY
+`
+
+func loadSchemas(t *testing.T) (*ast.Schema, *ast.Schema) {
+	t.Helper()
+	oldSchema := gqlparser.MustLoadSchema(&ast.Source{Name: "old.graphql", Input: oldSDL})
+	newSchema := gqlparser.MustLoadSchema(&ast.Source{Name: "new.graphql", Input: newSDL})
+	return oldSchema, newSchema
+}
+
+func TestDiff(t *testing.T) {
+	oldSchema, newSchema := loadSchemas(t)
+
+	var changes []string
+	for _, change := range schemadiff.Diff(oldSchema, newSchema) {
+		changes = append(changes, fmt.Sprintf(
+			"%s %s %s: %s",
+			change.Criticality,
+			change.Type,
+			change.Coordinate,
+			change.Description,
+		))
+	}
+
+	require.Equal(t, []string{
+		"BREAKING TYPE_CHANGED_KIND Date: Date changed from a Scalar type to an Object type.",
+		"BREAKING FIELD_CHANGED_KIND Filter.status: " +
+			"Filter.status changed type from Status to Status!.",
+		"SAFE FIELD_TYPE_CHANGED Filter.limit: Filter.limit changed type from Int! to Int.",
+		"BREAKING FIELD_REMOVED Filter.offset: Filter.offset was removed.",
+		"BREAKING REQUIRED_INPUT_FIELD_ADDED Filter.sort: " +
+			"A required field sort on input type Filter was added.",
+		"DANGEROUS OPTIONAL_INPUT_FIELD_ADDED Filter.page: " +
+			"An optional field page on input type Filter was added.",
+		"SAFE ARG_TYPE_CHANGED Query.user(id:): Query.user arg id has changed type from ID! to ID.",
+		"DANGEROUS ARG_DEFAULT_VALUE_CHANGE Query.user(first:): " +
+			"Query.user arg first has changed defaultValue from 10 to 20.",
+		"BREAKING ARG_CHANGED_KIND Query.user(after:): " +
+			"Query.user arg after has changed type from String to Int.",
+		"BREAKING ARG_REMOVED Query.user(format:): Query.user arg format was removed.",
+		"DANGEROUS OPTIONAL_ARG_ADDED Query.user(sort:): " +
+			"An optional arg sort on Query.user was added.",
+		"BREAKING REQUIRED_ARG_ADDED Query.user(page:): " +
+			"A required arg page on Query.user was added.",
+		"SAFE FIELD_TYPE_CHANGED Query.users: Query.users changed type from [User] to [User!].",
+		"BREAKING FIELD_REMOVED Query.legacy: Query.legacy was removed.",
+		"BREAKING FIELD_CHANGED_KIND Query.count: Query.count changed type from Int to String.",
+		"SAFE FIELD_ADDED Query.search: Query.search was added.",
+		"BREAKING TYPE_REMOVED_FROM_UNION Result: Post was removed from union type Result.",
+		"DANGEROUS TYPE_ADDED_TO_UNION Result: Comment was added to union type Result.",
+		"BREAKING VALUE_REMOVED_FROM_ENUM Status.BANNED: BANNED was removed from enum type Status.",
+		"DANGEROUS VALUE_ADDED_TO_ENUM Status.PENDING: PENDING was added to enum type Status.",
+		"SAFE FIELD_TYPE_CHANGED User.name: User.name changed type from String to String!.",
+		"BREAKING FIELD_CHANGED_KIND User.tags: User.tags changed type from [String] to String.",
+		"BREAKING FIELD_CHANGED_KIND User.friends: " +
+			"User.friends changed type from [User!]! to [User].",
+		"DANGEROUS INTERFACE_ADDED_TO_OBJECT User: Named added to interfaces implemented by User.",
+		"BREAKING IMPLEMENTED_INTERFACE_REMOVED User: User no longer implements interface Node.",
+		"SAFE TYPE_ADDED Comment: Comment was added.",
+		"SAFE TYPE_ADDED Named: Named was added.",
+		"BREAKING DIRECTIVE_ARG_REMOVED @cached(scope:): scope was removed from @cached.",
+		"BREAKING REQUIRED_DIRECTIVE_ARG_ADDED @cached(max:): " +
+			"A required arg max on directive @cached was added.",
+		"SAFE OPTIONAL_DIRECTIVE_ARG_ADDED @cached(lazy:): " +
+			"An optional arg lazy on directive @cached was added.",
+		"BREAKING DIRECTIVE_REPEATABLE_REMOVED @cached: Repeatable flag was removed from @cached.",
+		"BREAKING DIRECTIVE_LOCATION_REMOVED @cached: QUERY was removed from @cached.",
+		"SAFE DIRECTIVE_LOCATION_ADDED @cached: SUBSCRIPTION was added to @cached.",
+		"BREAKING DIRECTIVE_REMOVED @legacy: @legacy was removed.",
+		"SAFE DIRECTIVE_ADDED @live: @live was added.",
+	}, changes)
+}
+
+func TestDiffPositions(t *testing.T) {
+	oldSchema, newSchema := loadSchemas(t)
+
+	positions := map[string][2]*ast.Position{}
+	for _, change := range schemadiff.Diff(oldSchema, newSchema) {
+		positions[string(change.Type)+" "+change.Coordinate] = [2]*ast.Position{
+			change.OldPosition,
+			change.NewPosition,
+		}
+	}
+
+	pos := positions["FIELD_REMOVED Query.legacy"]
+	require.Equal(t, "old.graphql", pos[0].Src.Name)
+	require.Equal(t, 5, pos[0].Line)
+	require.Equal(t, "new.graphql", pos[1].Src.Name)
+	require.Equal(t, 2, pos[1].Line)
+
+	pos = positions["FIELD_ADDED Query.search"]
+	require.Equal(t, 2, pos[0].Line)
+	require.Equal(t, 6, pos[1].Line)
+
+	pos = positions["TYPE_REMOVED_FROM_UNION Result"]
+	require.Equal(t, 18, pos[0].Line)
+	require.Equal(t, 23, pos[0].Column)
+
+	pos = positions["DIRECTIVE_REMOVED @legacy"]
+	require.Equal(t, 33, pos[0].Line)
+	require.Nil(t, pos[1])
+
+	pos = positions["TYPE_ADDED Comment"]
+	require.Nil(t, pos[0])
+	require.Equal(t, 24, pos[1].Line)
+}
+
+func TestFindBreakingAndDangerousChanges(t *testing.T) {
+	oldSchema, newSchema := loadSchemas(t)
+
+	for _, change := range schemadiff.FindBreakingChanges(oldSchema, newSchema) {
+		require.Equal(t, schemadiff.Breaking, change.Criticality)
+	}
+	require.Len(t, schemadiff.FindBreakingChanges(oldSchema, newSchema), 19)
+	require.Len(t, schemadiff.FindDangerousChanges(oldSchema, newSchema), 6)
+
+	require.Empty(t, schemadiff.Diff(oldSchema, oldSchema))
+}
@@ -0,0 +1,412 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/vektah/gqlparser/v2/ast" //nolint:staticcheck // bad, yeah
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/introspection"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// BuildClientSchema builds a schema from the result of an introspection query,
+// like buildClientSchema in graphql-js. introspection is the JSON result, either
+// the object holding "__schema" or the whole response with it under "data".
+//
+// The schema is rebuilt as SDL and loaded along with the Prelude, so the
+// positions in any error refer to that SDL rather than to the JSON.
+func BuildClientSchema(introspection []byte) (*Schema, error) {
+	var result struct {
+		Schema *introspectionSchema `json:"__schema"`
+		Data   *struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(introspection, &result); err != nil {
+		return nil, gqlerror.Errorf("Invalid introspection result: %s", err.Error())
+	}
+	schema := result.Schema
+	if schema == nil && result.Data != nil {
+		schema = result.Data.Schema
+	}
+	if schema == nil {
+		return nil, gqlerror.Errorf(
+			`Invalid or incomplete introspection result. Ensure that you are passing the ` +
+				`"data" property of the introspection response and no "errors" were returned.`,
+		)
+	}
+
+	var sdl strings.Builder
+	if err := schema.writeSDL(&sdl); err != nil {
+		return nil, err
+	}
+	return LoadSchema(Prelude, &Source{Name: "introspection.graphql", Input: sdl.String()})
+}
+
+// The introspection result, as requested by the query graphql-js's
+// getIntrospectionQuery builds with every option enabled.
+type (
+	introspectionSchema struct {
+		Description      *string                   `json:"description"`
+		QueryType        *introspectionNamedType   `json:"queryType"`
+		MutationType     *introspectionNamedType   `json:"mutationType"`
+		SubscriptionType *introspectionNamedType   `json:"subscriptionType"`
+		Types            []*introspectionType      `json:"types"`
+		Directives       []*introspectionDirective `json:"directives"`
+	}
+
+	introspectionNamedType struct {
+		Name string `json:"name"`
+	}
+
+	introspectionType struct {
+		Kind           string                     `json:"kind"`
+		Name           string                     `json:"name"`
+		Description    *string                    `json:"description"`
+		SpecifiedByURL *string                    `json:"specifiedByURL"`
+		IsOneOf        bool                       `json:"isOneOf"`
+		Fields         []*introspectionField      `json:"fields"`
+		InputFields    []*introspectionInputValue `json:"inputFields"`
+		Interfaces     []*introspectionTypeRef    `json:"interfaces"`
+		EnumValues     []*introspectionEnumValue  `json:"enumValues"`
+		PossibleTypes  []*introspectionTypeRef    `json:"possibleTypes"`
+	}
+
+	introspectionTypeRef struct {
+		Kind   string                `json:"kind"`
+		Name   *string               `json:"name"`
+		OfType *introspectionTypeRef `json:"ofType"`
+	}
+
+	introspectionField struct {
+		Name              string                     `json:"name"`
+		Description       *string                    `json:"description"`
+		Args              []*introspectionInputValue `json:"args"`
+		Type              *introspectionTypeRef      `json:"type"`
+		IsDeprecated      bool                       `json:"isDeprecated"`
+		DeprecationReason *string                    `json:"deprecationReason"`
+	}
+
+	introspectionInputValue struct {
+		Name              string                `json:"name"`
+		Description       *string               `json:"description"`
+		Type              *introspectionTypeRef `json:"type"`
+		DefaultValue      *string               `json:"defaultValue"`
+		IsDeprecated      bool                  `json:"isDeprecated"`
+		DeprecationReason *string               `json:"deprecationReason"`
+	}
+
+	introspectionEnumValue struct {
+		Name              string  `json:"name"`
+		Description       *string `json:"description"`
+		IsDeprecated      bool    `json:"isDeprecated"`
+		DeprecationReason *string `json:"deprecationReason"`
+	}
+
+	introspectionDirective struct {
+		Name         string                     `json:"name"`
+		Description  *string                    `json:"description"`
+		IsRepeatable bool                       `json:"isRepeatable"`
+		Locations    []string                   `json:"locations"`
+		Args         []*introspectionInputValue `json:"args"`
+	}
+)
+
+var introspectionNameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// specifiedScalars are defined by the Prelude, so are not rebuilt.
+var specifiedScalars = map[string]bool{
+	"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true,
+}
+
+func (s *introspectionSchema) writeSDL(w *strings.Builder) error {
+	if s.QueryType != nil || s.MutationType != nil || s.SubscriptionType != nil {
+		writeDescription(w, "", s.Description)
+		w.WriteString("schema {\n")
+		for _, root := range []struct {
+			operation Operation
+			typ       *introspectionNamedType
+		}{
+			{Query, s.QueryType},
+			{Mutation, s.MutationType},
+			{Subscription, s.SubscriptionType},
+		} {
+			if root.typ == nil {
+				continue
+			}
+			if err := checkIntrospectionName(root.typ.Name); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "  %s: %s\n", root.operation, root.typ.Name)
+		}
+		w.WriteString("}\n")
+	}
+
+	for _, typ := range s.Types {
+		if strings.HasPrefix(typ.Name, "__") || specifiedScalars[typ.Name] {
+			continue
+		}
+		if err := typ.writeSDL(w); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range s.Directives {
+		if isBuiltinDirective(dir.Name) {
+			continue
+		}
+		if err := dir.writeSDL(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *introspectionType) writeSDL(w *strings.Builder) error {
+	if err := checkIntrospectionName(t.Name); err != nil {
+		return err
+	}
+	writeDescription(w, "", t.Description)
+
+	switch DefinitionKind(t.Kind) {
+	case Scalar:
+		fmt.Fprintf(w, "scalar %s", t.Name)
+		if t.SpecifiedByURL != nil {
+			fmt.Fprintf(w, " @specifiedBy(url: %s)", quoteIntrospectionString(*t.SpecifiedByURL))
+		}
+		w.WriteString("\n")
+	case Object, Interface:
+		keyword := "type"
+		if t.Kind == string(Interface) {
+			keyword = "interface"
+		}
+		fmt.Fprintf(w, "%s %s", keyword, t.Name)
+		for i, intf := range t.Interfaces {
+			name, err := intf.named()
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				w.WriteString(" implements ")
+			} else {
+				w.WriteString(" & ")
+			}
+			w.WriteString(name)
+		}
+		if len(t.Fields) > 0 {
+			w.WriteString(" {\n")
+			for _, field := range t.Fields {
+				if err := field.writeSDL(w); err != nil {
+					return err
+				}
+			}
+			w.WriteString("}")
+		}
+		w.WriteString("\n")
+	case Union:
+		fmt.Fprintf(w, "union %s", t.Name)
+		for i, member := range t.PossibleTypes {
+			name, err := member.named()
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				w.WriteString(" = ")
+			} else {
+				w.WriteString(" | ")
+			}
+			w.WriteString(name)
+		}
+		w.WriteString("\n")
+	case Enum:
+		fmt.Fprintf(w, "enum %s", t.Name)
+		if len(t.EnumValues) > 0 {
+			w.WriteString(" {\n")
+		}
+		for _, value := range t.EnumValues {
+			if err := checkIntrospectionName(value.Name); err != nil {
+				return err
+			}
+			writeDescription(w, "  ", value.Description)
+			fmt.Fprintf(w, "  %s", value.Name)
+			writeDeprecated(w, value.IsDeprecated, value.DeprecationReason)
+			w.WriteString("\n")
+		}
+		if len(t.EnumValues) > 0 {
+			w.WriteString("}")
+		}
+		w.WriteString("\n")
+	case InputObject:
+		fmt.Fprintf(w, "input %s", t.Name)
+		if t.IsOneOf {
+			w.WriteString(" @oneOf")
+		}
+		if len(t.InputFields) > 0 {
+			w.WriteString(" {\n")
+		}
+		for _, field := range t.InputFields {
+			if err := field.writeSDL(w, "  "); err != nil {
+				return err
+			}
+			w.WriteString("\n")
+		}
+		if len(t.InputFields) > 0 {
+			w.WriteString("}")
+		}
+		w.WriteString("\n")
+	default:
+		return gqlerror.Errorf(
+			"Invalid introspection result: type %s has unknown kind %s.",
+			t.Name,
+			t.Kind,
+		)
+	}
+	return nil
+}
+
+func (f *introspectionField) writeSDL(w *strings.Builder) error {
+	if err := checkIntrospectionName(f.Name); err != nil {
+		return err
+	}
+	writeDescription(w, "  ", f.Description)
+	fmt.Fprintf(w, "  %s", f.Name)
+	if err := writeArgs(w, f.Args); err != nil {
+		return err
+	}
+	typ, err := f.Type.typeString()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, ": %s", typ)
+	writeDeprecated(w, f.IsDeprecated, f.DeprecationReason)
+	w.WriteString("\n")
+	return nil
+}
+
+func (v *introspectionInputValue) writeSDL(w *strings.Builder, indent string) error {
+	if err := checkIntrospectionName(v.Name); err != nil {
+		return err
+	}
+	writeDescription(w, indent, v.Description)
+	typ, err := v.Type.typeString()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s%s: %s", indent, v.Name, typ)
+	if v.DefaultValue != nil {
+		// The default value is parsed rather than copied, so that it cannot
+		// add anything else to the SDL.
+		value, err := parser.ParseConstValue(&Source{Input: *v.DefaultValue})
+		if err != nil {
+			return gqlerror.Errorf(
+				"Invalid introspection result: default value %q of %s is not a value.",
+				*v.DefaultValue,
+				v.Name,
+			)
+		}
+		w.WriteString(" = " + introspection.PrintValue(value))
+	}
+	writeDeprecated(w, v.IsDeprecated, v.DeprecationReason)
+	return nil
+}
+
+func (d *introspectionDirective) writeSDL(w *strings.Builder) error {
+	if err := checkIntrospectionName(d.Name); err != nil {
+		return err
+	}
+	writeDescription(w, "", d.Description)
+	fmt.Fprintf(w, "directive @%s", d.Name)
+	if err := writeArgs(w, d.Args); err != nil {
+		return err
+	}
+	if d.IsRepeatable {
+		w.WriteString(" repeatable")
+	}
+	for i, location := range d.Locations {
+		if err := checkIntrospectionName(location); err != nil {
+			return err
+		}
+		if i == 0 {
+			w.WriteString(" on ")
+		} else {
+			w.WriteString(" | ")
+		}
+		w.WriteString(location)
+	}
+	w.WriteString("\n")
+	return nil
+}
+
+func writeArgs(w *strings.Builder, args []*introspectionInputValue) error {
+	if len(args) == 0 {
+		return nil
+	}
+	w.WriteString("(\n")
+	for _, arg := range args {
+		if err := arg.writeSDL(w, "    "); err != nil {
+			return err
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("  )")
+	return nil
+}
+
+func writeDescription(w *strings.Builder, indent string, description *string) {
+	if description != nil && *description != "" {
+		fmt.Fprintf(w, "%s%s\n", indent, quoteIntrospectionString(*description))
+	}
+}
+
+func writeDeprecated(w *strings.Builder, isDeprecated bool, reason *string) {
+	if !isDeprecated {
+		return
+	}
+	w.WriteString(" @deprecated")
+	if reason != nil {
+		fmt.Fprintf(w, "(reason: %s)", quoteIntrospectionString(*reason))
+	}
+}
+
+// quoteIntrospectionString quotes s as a GraphQL string. JSON string escapes
+// are a subset of GraphQL's.
+func quoteIntrospectionString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func (t *introspectionTypeRef) named() (string, error) {
+	if t == nil || t.Name == nil {
+		return "", gqlerror.Errorf("Invalid introspection result: missing type name.")
+	}
+	return *t.Name, checkIntrospectionName(*t.Name)
+}
+
+func (t *introspectionTypeRef) typeString() (string, error) {
+	if t == nil {
+		return "", gqlerror.Errorf("Invalid introspection result: missing type reference.")
+	}
+	switch t.Kind {
+	case "LIST":
+		elem, err := t.OfType.typeString()
+		return "[" + elem + "]", err
+	case "NON_NULL":
+		if t.OfType != nil && t.OfType.Kind == "NON_NULL" {
+			return "", gqlerror.Errorf("Invalid introspection result: NON_NULL of NON_NULL.")
+		}
+		elem, err := t.OfType.typeString()
+		return elem + "!", err
+	default:
+		return t.named()
+	}
+}
+
+func checkIntrospectionName(name string) error {
+	if !introspectionNameRegexp.MatchString(name) {
+		return gqlerror.Errorf("Invalid introspection result: %q is not a valid name.", name)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package validator_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func TestBuildClientSchema(t *testing.T) {
+	introspection, err := os.ReadFile("testdata/introspection.json")
+	require.NoError(t, err)
+
+	s, err := validator.BuildClientSchema(introspection)
+	require.NoError(t, err)
+
+	require.Equal(t, "A remote API.", s.Description)
+	require.Equal(t, "Query", s.Query.Name)
+	require.Nil(t, s.Mutation)
+
+	user := s.Types["User"]
+	require.Equal(t, ast.Object, user.Kind)
+	require.Equal(t, "A user\nof the API.", user.Description)
+	require.Equal(t, []string{"Node"}, user.Interfaces)
+	require.Len(t, s.GetPossibleTypes(s.Types["Node"]), 1)
+	require.Len(t, s.GetPossibleTypes(s.Types["SearchResult"]), 1)
+
+	search := s.Query.Fields.ForName("search")
+	require.Equal(t, "[SearchResult]!", search.Type.String())
+	filter := search.Arguments.ForName("filter").DefaultValue
+	require.Equal(t, ast.ObjectValue, filter.Kind)
+	require.Equal(t, `{status:ACTIVE,tags:["a","b"]}`, filter.String())
+
+	deprecated := s.Query.Fields.ForName("users").Directives.ForName("deprecated")
+	require.NotNil(t, deprecated)
+	reason := deprecated.Arguments.ForName("reason").Value
+	require.Equal(t, `"Use \"search\" instead."`, reason.String())
+	banned := s.Types["Status"].EnumValues.ForName("BANNED")
+	require.NotNil(t, banned.Directives.ForName("deprecated"))
+
+	require.NotNil(t, s.Types["UserBy"].Directives.ForName("oneOf"))
+	specifiedBy := s.Types["DateTime"].Directives.ForName("specifiedBy")
+	require.Equal(
+		t,
+		`"https://scalars.graphql.org/andimarek/date-time"`,
+		specifiedBy.Arguments.ForName("url").Value.String(),
+	)
+
+	cached := s.Directives["cached"]
+	require.True(t, cached.IsRepeatable)
+	require.Equal(
+		t,
+		[]ast.DirectiveLocation{ast.LocationField, ast.LocationQuery},
+		cached.Locations,
+	)
+	require.Equal(t, "60", cached.Arguments.ForName("ttl").DefaultValue.String())
+
+	// The Prelude's definitions are used for the built-in types and directives.
+	require.True(t, s.Types["String"].BuiltIn)
+	require.Contains(t, s.Directives["skip"].Locations, ast.LocationInlineFragment)
+
+	query, err := parser.ParseQuery(&ast.Source{Input: `{
+		search(filter: {by: {id: "1"}}) { ... on User { id joined status } }
+		node(id: "1") { id }
+	}`})
+	require.NoError(t, err)
+	require.Empty(t, validator.ValidateWithRules(s, query, nil))
+}
+
+func TestBuildClientSchemaErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input string
+		err   string
+	}{
+		"invalid json": {
+			input: `{`,
+			err:   "input: Invalid introspection result: unexpected end of JSON input",
+		},
+		"missing __schema": {
+			input: `{"errors": [{"message": "introspection disabled"}]}`,
+			err: `input: Invalid or incomplete introspection result. Ensure that you are passing ` +
+				`the "data" property of the introspection response and no "errors" were returned.`,
+		},
+		"invalid name": {
+			input: `{"__schema": {"types": [{"kind": "SCALAR", "name": "Date }"}]}}`,
+			err:   `input: Invalid introspection result: "Date }" is not a valid name.`,
+		},
+		"unknown kind": {
+			input: `{"__schema": {"types": [{"kind": "THING", "name": "Thing"}]}}`,
+			err:   "input: Invalid introspection result: type Thing has unknown kind THING.",
+		},
+		"default value that is not a value": {
+			input: `{"__schema": {"queryType": {"name": "Query"}, "types": [{
+				"kind": "OBJECT", "name": "Query", "fields": [{
+					"name": "a",
+					"args": [{
+						"name": "x",
+						"type": {"kind": "SCALAR", "name": "Int"},
+						"defaultValue": "1): Int\n  injected: String\n  b(y: Int = 2"
+					}],
+					"type": {"kind": "SCALAR", "name": "Int"}
+				}]
+			}]}}`,
+			err: `input: Invalid introspection result: default value ` +
+				`"1): Int\n  injected: String\n  b(y: Int = 2" of x is not a value.`,
+		},
+		"undefined type": {
+			input: `{"__schema": {"queryType": {"name": "Query"}, "types": [{
+				"kind": "OBJECT", "name": "Query", "fields": [
+					{"name": "a", "args": [], "type": {"kind": "OBJECT", "name": "Missing"}}
+				]
+			}]}}`,
+			err: "introspection.graphql:5:6: Undefined type Missing.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := validator.BuildClientSchema([]byte(tc.input))
+			require.EqualError(t, err, tc.err)
+			var gqlErr *gqlerror.Error
+			require.ErrorAs(t, err, &gqlErr)
+		})
+	}
+}
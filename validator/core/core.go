@@ -13,6 +13,14 @@ type Rule struct {
 	RuleFunc RuleFunc
 }
 
+type SchemaRuleFunc func(observers *SchemaEvents, addError AddErrFunc)
+
+// SchemaRule is a named SDL validation rule, the schema counterpart of Rule.
+type SchemaRule struct {
+	Name     string
+	RuleFunc SchemaRuleFunc
+}
+
 // NameSorter sorts Rules by name.
 // usage: sort.Sort(core.NameSorter(specifiedRules))
 type NameSorter []Rule
@@ -34,6 +34,17 @@ func At(position *ast.Position) ErrorOption {
 	}
 }
 
+// Code sets the code extension of the error, which lets clients tell apart
+// errors that they handle differently.
+func Code(code string) ErrorOption {
+	return func(err *gqlerror.Error) {
+		if err.Extensions == nil {
+			err.Extensions = map[string]any{}
+		}
+		err.Extensions["code"] = code
+	}
+}
+
 func SuggestListQuoted(prefix, typed string, suggestions []string) ErrorOption {
 	suggested := SuggestionList(typed, suggestions)
 	return func(err *gqlerror.Error) {
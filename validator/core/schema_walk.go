@@ -0,0 +1,269 @@
+package core
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SchemaEvents is the Events equivalent for SDL rules, which observe a built
+// schema rather than a query document.
+type SchemaEvents struct {
+	typ                 []func(walker *SchemaWalker, def *ast.Definition)
+	field               []func(walker *SchemaWalker, field *ast.FieldDefinition)
+	argument            []func(walker *SchemaWalker, arg *ast.ArgumentDefinition)
+	enumValue           []func(walker *SchemaWalker, value *ast.EnumValueDefinition)
+	directive           []func(walker *SchemaWalker, directive *ast.Directive)
+	directiveList       []func(walker *SchemaWalker, directives []*ast.Directive)
+	directiveDefinition []func(walker *SchemaWalker, def *ast.DirectiveDefinition)
+}
+
+func (o *SchemaEvents) OnType(f func(walker *SchemaWalker, def *ast.Definition)) {
+	o.typ = append(o.typ, f)
+}
+
+func (o *SchemaEvents) OnField(f func(walker *SchemaWalker, field *ast.FieldDefinition)) {
+	o.field = append(o.field, f)
+}
+
+func (o *SchemaEvents) OnArgument(f func(walker *SchemaWalker, arg *ast.ArgumentDefinition)) {
+	o.argument = append(o.argument, f)
+}
+
+func (o *SchemaEvents) OnEnumValue(
+	f func(walker *SchemaWalker, value *ast.EnumValueDefinition),
+) {
+	o.enumValue = append(o.enumValue, f)
+}
+
+func (o *SchemaEvents) OnDirective(f func(walker *SchemaWalker, directive *ast.Directive)) {
+	o.directive = append(o.directive, f)
+}
+
+// OnDirectiveList is called once for each place directives were written,
+// so a type and each of its extensions are reported separately.
+func (o *SchemaEvents) OnDirectiveList(
+	f func(walker *SchemaWalker, directives []*ast.Directive),
+) {
+	o.directiveList = append(o.directiveList, f)
+}
+
+func (o *SchemaEvents) OnDirectiveDefinition(
+	f func(walker *SchemaWalker, def *ast.DirectiveDefinition),
+) {
+	o.directiveDefinition = append(o.directiveDefinition, f)
+}
+
+// WalkSchema visits the types and directive definitions of schema. document is
+// the source the schema was built from, and when given only the types and
+// directives it defines or extends are visited. It may be nil to visit
+// everything, but then the directives of the schema and its types are only
+// seen merged across their extensions, so OnDirectiveList is not called for
+// them.
+//
+// Fields, enum values, arguments and directive definitions are reported before
+// what they hold. A type is reported after its fields and enum values, but
+// before its own directives.
+func WalkSchema(schema *ast.Schema, document *ast.SchemaDocument, observers *SchemaEvents) {
+	w := SchemaWalker{
+		Observers: observers,
+		Schema:    schema,
+		Document:  document,
+	}
+
+	w.walk()
+}
+
+type SchemaWalker struct {
+	Observers *SchemaEvents
+	Schema    *ast.Schema
+	Document  *ast.SchemaDocument
+
+	CurrentType                *ast.Definition
+	CurrentField               *ast.FieldDefinition
+	CurrentDirectiveDefinition *ast.DirectiveDefinition
+
+	defined    map[*ast.Definition]bool
+	extensions map[string]ast.DefinitionList
+}
+
+func (w *SchemaWalker) walk() {
+	if w.Document != nil {
+		w.defined = make(map[*ast.Definition]bool, len(w.Document.Definitions))
+		for _, def := range w.Document.Definitions {
+			w.defined[def] = true
+		}
+		w.extensions = make(map[string]ast.DefinitionList, len(w.Document.Extensions))
+		for _, ext := range w.Document.Extensions {
+			w.extensions[ext.Name] = append(w.extensions[ext.Name], ext)
+		}
+
+		for _, schema := range w.Document.Schema {
+			w.walkDirectives(schema.Directives, ast.LocationSchema)
+		}
+		for _, ext := range w.Document.SchemaExtension {
+			w.walkDirectives(ext.Directives, ast.LocationSchema)
+		}
+	} else {
+		w.visitDirectives(w.Schema.SchemaDirectives, ast.LocationSchema)
+	}
+
+	for _, typ := range w.typeNames() {
+		if def := w.Schema.Types[typ]; def != nil {
+			w.walkType(def)
+		}
+	}
+
+	for _, directive := range w.directiveNames() {
+		if def := w.Schema.Directives[directive]; def != nil {
+			w.walkDirectiveDefinition(def)
+		}
+	}
+}
+
+func (w *SchemaWalker) typeNames() []string {
+	var names []string
+	if w.Document == nil {
+		names = make([]string, 0, len(w.Schema.Types))
+		for name := range w.Schema.Types {
+			names = append(names, name)
+		}
+	} else {
+		for _, def := range w.Document.Definitions {
+			names = append(names, def.Name)
+		}
+		for _, ext := range w.Document.Extensions {
+			names = append(names, ext.Name)
+		}
+	}
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+func (w *SchemaWalker) directiveNames() []string {
+	var names []string
+	if w.Document == nil {
+		names = make([]string, 0, len(w.Schema.Directives))
+		for name := range w.Schema.Directives {
+			names = append(names, name)
+		}
+	} else {
+		for _, def := range w.Document.Directives {
+			names = append(names, def.Name)
+		}
+	}
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+func (w *SchemaWalker) walkType(def *ast.Definition) {
+	w.CurrentType = def
+
+	fieldLocation := ast.LocationFieldDefinition
+	if def.Kind == ast.InputObject {
+		fieldLocation = ast.LocationInputFieldDefinition
+	}
+	for _, field := range def.Fields {
+		w.CurrentField = field
+		for _, v := range w.Observers.field {
+			v(w, field)
+		}
+		for _, arg := range field.Arguments {
+			w.walkArgument(arg)
+		}
+		w.walkDirectives(field.Directives, fieldLocation)
+	}
+	w.CurrentField = nil
+
+	for _, value := range def.EnumValues {
+		for _, v := range w.Observers.enumValue {
+			v(w, value)
+		}
+		w.walkDirectives(value.Directives, ast.LocationEnumValue)
+	}
+
+	for _, v := range w.Observers.typ {
+		v(w, def)
+	}
+
+	w.walkTypeDirectives(def)
+	w.CurrentType = nil
+}
+
+// walkTypeDirectives walks the directives of def, which are merged across its
+// definition and extensions, then reports the list written at each of them in
+// the document.
+func (w *SchemaWalker) walkTypeDirectives(def *ast.Definition) {
+	location := ast.DirectiveLocation(def.Kind)
+	w.visitDirectives(def.Directives, location)
+	if w.Document == nil {
+		return
+	}
+
+	var extensions []ast.DirectiveList
+	fromExtension := map[*ast.Directive]bool{}
+	for _, ext := range w.extensions[def.Name] {
+		if ext.Kind != def.Kind {
+			continue
+		}
+		extensions = append(extensions, ext.Directives)
+		for _, dir := range ext.Directives {
+			fromExtension[dir] = true
+		}
+	}
+
+	lists := extensions
+	if w.defined[def] {
+		own := make(ast.DirectiveList, 0, len(def.Directives))
+		for _, dir := range def.Directives {
+			if !fromExtension[dir] {
+				own = append(own, dir)
+			}
+		}
+		lists = append([]ast.DirectiveList{own}, extensions...)
+	}
+	for _, dirs := range lists {
+		for _, v := range w.Observers.directiveList {
+			v(w, dirs)
+		}
+	}
+}
+
+func (w *SchemaWalker) walkArgument(arg *ast.ArgumentDefinition) {
+	for _, v := range w.Observers.argument {
+		v(w, arg)
+	}
+	w.walkDirectives(arg.Directives, ast.LocationArgumentDefinition)
+}
+
+func (w *SchemaWalker) walkDirectiveDefinition(def *ast.DirectiveDefinition) {
+	w.CurrentDirectiveDefinition = def
+	for _, v := range w.Observers.directiveDefinition {
+		v(w, def)
+	}
+	for _, arg := range def.Arguments {
+		w.walkArgument(arg)
+	}
+	w.CurrentDirectiveDefinition = nil
+}
+
+func (w *SchemaWalker) walkDirectives(directives []*ast.Directive, location ast.DirectiveLocation) {
+	w.visitDirectives(directives, location)
+	for _, v := range w.Observers.directiveList {
+		v(w, directives)
+	}
+}
+
+func (w *SchemaWalker) visitDirectives(
+	directives []*ast.Directive,
+	location ast.DirectiveLocation,
+) {
+	for _, dir := range directives {
+		dir.Definition = w.Schema.Directives[dir.Name]
+		dir.Location = location
+		for _, v := range w.Observers.directive {
+			v(w, dir)
+		}
+	}
+}
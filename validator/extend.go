@@ -0,0 +1,372 @@
+package validator
+
+import (
+	"maps"
+	"slices"
+
+	. "github.com/vektah/gqlparser/v2/ast" //nolint:staticcheck // bad, yeah
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/sdlrules"
+)
+
+// ExtendSchema returns a new schema with the definitions and extensions of doc
+// applied to base, which is left untouched. doc is validated against base, so
+// it may extend the types and use the directives already defined there, and
+// the first error found is returned.
+//
+// Like graphql-js, root operation types are only taken from base and any
+// schema extension in doc; a new type named Mutation does not become the
+// mutation root by itself.
+func ExtendSchema(base *Schema, doc *SchemaDocument) (*Schema, error) {
+	schema, errs := extendSchema(base, doc, sdlrules.NewDefaultRules())
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return schema, nil
+}
+
+func extendSchema(
+	base *Schema,
+	doc *SchemaDocument,
+	rules *sdlrules.Rules,
+) (*Schema, gqlerror.List) {
+	var errs gqlerror.List
+	schema := Schema{
+		Query:            base.Query,
+		Mutation:         base.Mutation,
+		Subscription:     base.Subscription,
+		SchemaDirectives: slices.Clone(base.SchemaDirectives),
+		Types:            make(map[string]*Definition, len(base.Types)+len(doc.Definitions)),
+		Directives:       make(map[string]*DirectiveDefinition, len(base.Directives)),
+		PossibleTypes:    make(map[string][]*Definition, len(base.PossibleTypes)),
+		Implements:       make(map[string][]*Definition, len(base.Implements)),
+		Scalars:          maps.Clone(base.Scalars),
+		Description:      base.Description,
+		Comment:          base.Comment,
+	}
+	for name, def := range base.Types {
+		schema.Types[name] = def
+	}
+	for name, def := range base.Directives {
+		schema.Directives[name] = def
+	}
+
+	// Definitions from base are copied before they are changed or walked, and
+	// the copies replace the originals wherever base refers to them. The walk
+	// writes to directives, so those are copied along with everything holding
+	// them.
+	copied := map[*Definition]*Definition{}
+	copyDefinition := func(def *Definition) *Definition {
+		if c := copied[def]; c != nil {
+			return c
+		}
+		c := *def
+		c.Directives = copyDirectives(def.Directives)
+		c.Interfaces = slices.Clone(def.Interfaces)
+		c.Fields = copyFields(def.Fields)
+		c.Types = slices.Clone(def.Types)
+		c.TypePositions = slices.Clone(def.TypePositions)
+		c.EnumValues = copyEnumValues(def.EnumValues)
+		if def == base.Query {
+			// Added back once the new fields are validated.
+			c.Fields = slices.DeleteFunc(c.Fields, func(field *FieldDefinition) bool {
+				return field.Name == "__schema" || field.Name == "__type"
+			})
+		}
+		copied[def] = &c
+		schema.Types[c.Name] = &c
+		return &c
+	}
+
+	defs := make(DefinitionList, 0, len(doc.Definitions))
+	for _, def := range doc.Definitions {
+		if existing := schema.Types[def.Name]; existing != nil {
+			if base.Types[def.Name] == existing {
+				errs = append(errs, gqlerror.ErrorPosf(
+					def.Position,
+					`Type "%s" already exists in the schema. `+
+						`It cannot also be defined in this type definition.`,
+					def.Name,
+				))
+			} else {
+				errs = append(
+					errs,
+					gqlerror.ErrorPosf(def.Position, "Cannot redeclare type %s.", def.Name),
+				)
+			}
+			continue
+		}
+		schema.Types[def.Name] = def
+		defs = append(defs, def)
+	}
+
+	// Only what is new needs adding to PossibleTypes and Implements; base
+	// already has the rest.
+	added := make(DefinitionList, 0, len(defs)+len(doc.Extensions))
+	added = append(added, defs...)
+	extensions := make(DefinitionList, 0, len(doc.Extensions))
+	extendedInterfaces := map[string]bool{}
+	for _, ext := range doc.Extensions {
+		def := schema.Types[ext.Name]
+		switch {
+		case def == nil:
+			def = &Definition{
+				Kind:     ext.Kind,
+				Name:     ext.Name,
+				Position: ext.Position,
+			}
+			schema.Types[ext.Name] = def
+			added = append(added, def)
+		case def.Kind != ext.Kind:
+			errs = append(errs, gqlerror.ErrorPosf(
+				ext.Position,
+				"Cannot extend type %s because the base type is a %s, not %s.",
+				ext.Name,
+				def.Kind,
+				ext.Kind,
+			))
+			continue
+		case base.Types[ext.Name] != nil:
+			def = copyDefinition(def)
+			added = append(added, &Definition{
+				Kind:       def.Kind,
+				Name:       def.Name,
+				Interfaces: ext.Interfaces,
+				Types:      ext.Types,
+			})
+			if def.Kind == Interface {
+				extendedInterfaces[def.Name] = true
+			}
+		}
+
+		mergeExtension(def, ext)
+		extensions = append(extensions, ext)
+	}
+
+	// Types that implement an extended interface may no longer satisfy it, so
+	// they are validated along with doc, as if they had been extended too.
+	for name, def := range base.Types {
+		if copied[def] != nil || !slices.ContainsFunc(def.Interfaces, func(intf string) bool {
+			return extendedInterfaces[intf]
+		}) {
+			continue
+		}
+		copyDefinition(def)
+		extensions = append(extensions, &Definition{Kind: def.Kind, Name: name})
+	}
+
+	for _, def := range added {
+		// Extensions of base types are added as a stand in holding just the new
+		// interfaces and members, so point at the real definition.
+		target := schema.Types[def.Name]
+		switch def.Kind {
+		case Union:
+			for _, t := range def.Types {
+				schema.AddPossibleType(def.Name, schema.Types[t])
+				schema.AddImplements(t, target)
+			}
+		case InputObject, Object:
+			for _, intf := range def.Interfaces {
+				schema.AddPossibleType(intf, target)
+				schema.AddImplements(def.Name, schema.Types[intf])
+			}
+			if target == def {
+				schema.AddPossibleType(def.Name, def)
+			}
+		case Interface:
+			for _, intf := range def.Interfaces {
+				schema.AddPossibleType(intf, target)
+				schema.AddImplements(def.Name, schema.Types[intf])
+			}
+		}
+	}
+
+	directives := make(DirectiveDefinitionList, 0, len(doc.Directives))
+	for _, dir := range doc.Directives {
+		if existing := schema.Directives[dir.Name]; existing != nil {
+			switch {
+			case isBuiltinDirective(dir.Name):
+				// The last definition is kept, as validateSchemaDocument does.
+				schema.Directives[dir.Name] = dir
+				directives = append(directives, dir)
+			case base.Directives[dir.Name] == existing:
+				errs = append(errs, gqlerror.ErrorPosf(
+					dir.Position,
+					`Directive "@%s" already exists in the schema. It cannot be redefined.`,
+					dir.Name,
+				))
+			default:
+				errs = append(errs, gqlerror.ErrorPosf(
+					dir.Position,
+					"Cannot redeclare directive %s.",
+					dir.Name,
+				))
+			}
+			continue
+		}
+		schema.Directives[dir.Name] = dir
+		directives = append(directives, dir)
+	}
+
+	if len(doc.Schema) > 0 &&
+		(len(base.SchemaDirectives) > 0 ||
+			base.Query != nil || base.Mutation != nil || base.Subscription != nil) {
+		errs = append(errs, gqlerror.ErrorPosf(
+			doc.Schema[0].Position,
+			"Cannot define a new schema within a schema extension.",
+		))
+	} else if len(doc.Schema) > 1 {
+		errs = append(errs, gqlerror.ErrorPosf(
+			doc.Schema[1].Position,
+			"Cannot have multiple schema entry points, consider schema extensions instead.",
+		))
+	}
+
+	definedOperations := map[Operation]bool{}
+	var operationTypes []*OperationTypeDefinition
+	if len(doc.Schema) > 0 {
+		operationTypes = append(operationTypes, doc.Schema[0].OperationTypes...)
+		schema.SchemaDirectives = append(schema.SchemaDirectives, doc.Schema[0].Directives...)
+		if doc.Schema[0].Description != "" {
+			schema.Description = doc.Schema[0].Description
+		}
+	}
+	for _, ext := range doc.SchemaExtension {
+		operationTypes = append(operationTypes, ext.OperationTypes...)
+		schema.SchemaDirectives = append(schema.SchemaDirectives, ext.Directives...)
+	}
+	for _, entrypoint := range operationTypes {
+		var existing *Definition
+		switch entrypoint.Operation {
+		case Query:
+			existing = base.Query
+		case Mutation:
+			existing = base.Mutation
+		case Subscription:
+			existing = base.Subscription
+		}
+		if existing != nil {
+			errs = append(errs, gqlerror.ErrorPosf(
+				entrypoint.Position,
+				"Type for %s already defined in the schema. It cannot be redefined.",
+				entrypoint.Operation,
+			))
+			continue
+		}
+		if definedOperations[entrypoint.Operation] {
+			errs = append(errs, duplicateOperationTypeError(entrypoint))
+			continue
+		}
+		definedOperations[entrypoint.Operation] = true
+		def := schema.Types[entrypoint.Type]
+		if def == nil {
+			errs = append(errs, gqlerror.ErrorPosf(
+				entrypoint.Position,
+				"Schema root %s refers to a type %s that does not exist.",
+				entrypoint.Operation,
+				entrypoint.Type,
+			))
+			continue
+		}
+		switch entrypoint.Operation {
+		case Query:
+			schema.Query = def
+		case Mutation:
+			schema.Mutation = def
+		case Subscription:
+			schema.Subscription = def
+		}
+	}
+
+	for old, def := range copied {
+		switch old {
+		case schema.Query:
+			schema.Query = def
+		case schema.Mutation:
+			schema.Mutation = def
+		case schema.Subscription:
+			schema.Subscription = def
+		}
+	}
+	replace := func(defs []*Definition) []*Definition {
+		defs = slices.Clone(defs)
+		for i, def := range defs {
+			if c := copied[def]; c != nil {
+				defs[i] = c
+			}
+		}
+		return defs
+	}
+	for name, defs := range base.PossibleTypes {
+		schema.PossibleTypes[name] = append(replace(defs), schema.PossibleTypes[name]...)
+	}
+	for name, defs := range base.Implements {
+		schema.Implements[name] = append(replace(defs), schema.Implements[name]...)
+	}
+
+	// Only what doc added is walked, as the walk would otherwise reach the
+	// definitions of base that doc failed to redefine, and write to them.
+	walkDoc := &SchemaDocument{
+		Schema:          doc.Schema,
+		SchemaExtension: doc.SchemaExtension,
+		Directives:      directives,
+		Definitions:     defs,
+		Extensions:      extensions,
+	}
+	errs = append(errs, walkSchemaRules(&schema, walkDoc, rules)...)
+
+	if schema.Query != nil && schema.Query.Fields.ForName("__schema") == nil {
+		addIntrospectionFields(&schema)
+	}
+
+	return &schema, errs
+}
+
+// copyDirectives copies directives, which the schema walker writes to.
+func copyDirectives(directives DirectiveList) DirectiveList {
+	if directives == nil {
+		return nil
+	}
+	copied := make(DirectiveList, len(directives))
+	for i, dir := range directives {
+		c := *dir
+		copied[i] = &c
+	}
+	return copied
+}
+
+// copyFields copies fields with their directives and arguments.
+func copyFields(fields FieldList) FieldList {
+	if fields == nil {
+		return nil
+	}
+	copied := make(FieldList, len(fields))
+	for i, field := range fields {
+		c := *field
+		c.Directives = copyDirectives(field.Directives)
+		if field.Arguments != nil {
+			c.Arguments = make(ArgumentDefinitionList, len(field.Arguments))
+			for j, arg := range field.Arguments {
+				argCopy := *arg
+				argCopy.Directives = copyDirectives(arg.Directives)
+				c.Arguments[j] = &argCopy
+			}
+		}
+		copied[i] = &c
+	}
+	return copied
+}
+
+// copyEnumValues copies values with their directives.
+func copyEnumValues(values EnumValueList) EnumValueList {
+	if values == nil {
+		return nil
+	}
+	copied := make(EnumValueList, len(values))
+	for i, value := range values {
+		c := *value
+		c.Directives = copyDirectives(value.Directives)
+		copied[i] = &c
+	}
+	return copied
+}
@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func loadBaseSchema(t *testing.T) *ast.Schema {
+	t.Helper()
+	s, err := LoadSchema(Prelude, &ast.Source{Name: "base", Input: `
+		directive @tenant on FIELD_DEFINITION
+		interface Node { id: ID! }
+		type User implements Node { id: ID! name: String }
+		union Result = User
+		type Query { node(id: ID!): Node }
+	`})
+	require.NoError(t, err)
+	return s
+}
+
+func extend(t *testing.T, base *ast.Schema, input string) (*ast.Schema, error) {
+	t.Helper()
+	doc, err := parser.ParseSchema(&ast.Source{Name: "ext", Input: input})
+	require.NoError(t, err)
+	return ExtendSchema(base, doc)
+}
+
+func TestExtendSchema(t *testing.T) {
+	t.Run("adds types and fields without changing base", func(t *testing.T) {
+		base := loadBaseSchema(t)
+		baseQueryFields := len(base.Query.Fields)
+
+		s, err := extend(t, base, `
+			type Team implements Node { id: ID! members: [User!]! }
+			extend union Result = Team
+			extend type Query { teams: [Team!]! @tenant }
+		`)
+		require.NoError(t, err)
+
+		require.NotNil(t, s.Types["Team"])
+		require.NotNil(t, s.Query.Fields.ForName("teams"))
+		require.Same(t, s.Types["Query"], s.Query)
+		require.Len(t, s.Query.Fields, baseQueryFields+1)
+		require.NotNil(t, s.Query.Fields.ForName("__schema"))
+		require.Equal(t, "tenant", s.Query.Fields.ForName("teams").Directives[0].Definition.Name)
+
+		require.Equal(t, []string{"User", "Team"}, definitionNames(s.PossibleTypes["Node"]))
+		require.Equal(t, []string{"User", "Team"}, definitionNames(s.PossibleTypes["Result"]))
+		require.Equal(t, []string{"Node", "Result"}, definitionNames(s.Implements["Team"]))
+
+		require.Nil(t, base.Types["Team"])
+		require.Nil(t, base.Query.Fields.ForName("teams"))
+		require.Len(t, base.Query.Fields, baseQueryFields)
+		require.Equal(t, []string{"User"}, definitionNames(base.PossibleTypes["Node"]))
+		require.Equal(t, []string{"User"}, definitionNames(base.PossibleTypes["Result"]))
+	})
+
+	t.Run("replaces extended definitions everywhere", func(t *testing.T) {
+		base := loadBaseSchema(t)
+
+		s, err := extend(t, base, `extend type User { email: String }`)
+		require.NoError(t, err)
+
+		user := s.Types["User"]
+		require.NotSame(t, base.Types["User"], user)
+		require.NotNil(t, user.Fields.ForName("email"))
+		require.Nil(t, base.Types["User"].Fields.ForName("email"))
+		require.Same(t, user, s.PossibleTypes["Node"][0])
+		require.Same(t, user, s.PossibleTypes["Result"][0])
+		require.Same(t, base.Types["User"], base.PossibleTypes["Node"][0])
+	})
+
+	t.Run("adds a root operation type", func(t *testing.T) {
+		base := loadBaseSchema(t)
+
+		s, err := extend(t, base, `
+			type Mutation { rename(name: String!): User }
+			extend schema { mutation: Mutation }
+		`)
+		require.NoError(t, err)
+		require.Same(t, s.Types["Mutation"], s.Mutation)
+		require.Nil(t, base.Mutation)
+	})
+
+	t.Run("replaces a builtin directive", func(t *testing.T) {
+		base := loadBaseSchema(t)
+
+		s, err := extend(t, base, `
+			directive @deprecated(reason: String) on OBJECT
+			type Old @deprecated { id: ID }
+		`)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			[]ast.DirectiveLocation{ast.LocationObject},
+			s.Directives["deprecated"].Locations,
+		)
+		require.NotEqual(
+			t,
+			[]ast.DirectiveLocation{ast.LocationObject},
+			base.Directives["deprecated"].Locations,
+		)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "redefining a type",
+			input: `type User { id: ID! }`,
+			err: `ext:1:6: Type "User" already exists in the schema. ` +
+				`It cannot also be defined in this type definition.`,
+		},
+		{
+			name:  "extending with the wrong kind",
+			input: `extend interface User { email: String }`,
+			err: "ext:1:18: Cannot extend type User " +
+				"because the base type is a OBJECT, not INTERFACE.",
+		},
+		{
+			name:  "redefining a field",
+			input: `extend type User { name: String }`,
+			err:   "ext:1:20: Field User.name can only be defined once.",
+		},
+		{
+			name:  "referring to an undefined type",
+			input: `extend type User { team: Team }`,
+			err:   "ext:1:26: Undefined type Team.",
+		},
+		{
+			name:  "redefining a directive",
+			input: `directive @tenant on OBJECT`,
+			err: `ext:1:12: Directive "@tenant" already exists in the schema. ` +
+				`It cannot be redefined.`,
+		},
+		{
+			name:  "redefining a root operation type",
+			input: `type Other { id: ID } extend schema { query: Other }`,
+			err: "ext:1:39: Type for query already defined in the schema. " +
+				"It cannot be redefined.",
+		},
+		{
+			name:  "extending an interface its implementations do not satisfy",
+			input: `extend interface Node { createdAt: String }`,
+			err:   "base:4:8: For User to implement Node it must have a field called createdAt.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			base := loadBaseSchema(t)
+			_, err := extend(t, base, tc.input)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// Extensions of one base schema are built concurrently, and must not write to
+// the nodes they share with it. Run with -race.
+func TestExtendSchemaConcurrently(t *testing.T) {
+	base, err := LoadSchema(Prelude, &ast.Source{Name: "base", Input: `
+		directive @tenant(name: String @deprecated) on FIELD_DEFINITION | OBJECT | ENUM_VALUE
+		interface Node { id: ID! @tenant }
+		type User implements Node @tenant {
+			id: ID! @tenant
+			name(format: String @deprecated): String
+		}
+		enum Role { ADMIN @tenant }
+		type Query { node(id: ID!): Node }
+	`})
+	require.NoError(t, err)
+
+	inputs := []string{
+		`extend type User { email: String }`,
+		`extend interface Node { createdAt: String }`,
+		`extend enum Role { GUEST }`,
+		`extend type Query { me: User }`,
+		`type User { id: ID! }`,
+		`extend interface User { email: String }`,
+		`directive @tenant on OBJECT`,
+		`directive @deprecated(reason: String) on FIELD_DEFINITION`,
+	}
+	docs := make([]*ast.SchemaDocument, len(inputs))
+	for i, input := range inputs {
+		docs[i], err = parser.ParseSchema(&ast.Source{Name: "ext", Input: input})
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, doc := range docs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = ExtendSchema(base, doc)
+		}()
+	}
+	wg.Wait()
+}
+
+func definitionNames(defs []*ast.Definition) []string {
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		names[i] = def.Name
+	}
+	return names
+}
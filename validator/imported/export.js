@@ -45,7 +45,20 @@ directive @defer(
 ) on FRAGMENT_SPREAD | INLINE_FRAGMENT
 \n`
 
-  const customGeneratedContent = generatedText + builtinScalars + deferDirective
+  const streamDirective = `
+"Directs the executor to stream plural fields when the \`if\` argument is true or undefined."
+directive @stream(
+  "Stream when true or undefined."
+  if: Boolean = true,
+  "Unique name"
+  label: String
+  "Number of items to return immediately"
+  initialCount: Int = 0
+) on FIELD
+\n`
+
+  const customGeneratedContent =
+    generatedText + builtinScalars + deferDirective + streamDirective
 
   const schema = new GraphQLSchema({});
   const output = customGeneratedContent + printIntrospectionSchema(schema);
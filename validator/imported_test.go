@@ -16,6 +16,8 @@ import (
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/rules"
 )
 
 type Spec struct {
@@ -54,6 +56,14 @@ func TestValidation(t *testing.T) {
 		}
 		schemas = append(schemas, schema)
 	}
+	// graphql-js gives CustomScalar a parser that rejects every literal.
+	for _, schema := range schemas {
+		if schema.Types["CustomScalar"] != nil {
+			schema.RegisterScalar("CustomScalar", func(value *ast.Value) error {
+				return fmt.Errorf("CustomScalar cannot represent %s", value.String())
+			}, nil)
+		}
+	}
 
 	err := filepath.Walk("./", func(path string, info os.FileInfo, err error) error {
 		if info.IsDir() || !strings.HasSuffix(path, ".spec.yml") {
@@ -102,8 +112,13 @@ func runSpec(t *testing.T, schemas []*ast.Schema, deviations []*Deviation, filen
 				} else {
 					schema = schemas[idx]
 				}
-				//nolint:staticcheck
-				_, errList := gqlparser.LoadQuery(schema, spec.Query)
+				var errList gqlerror.List
+				if specRules := optionalSpecRules(spec); specRules != nil {
+					_, errList = gqlparser.LoadQueryWithRules(schema, spec.Query, specRules)
+				} else {
+					//nolint:staticcheck
+					_, errList = gqlparser.LoadQuery(schema, spec.Query)
+				}
 				var finalErrors gqlerror.List
 				for _, err := range errList {
 					// ignore errors from other rules
@@ -220,7 +235,11 @@ func runSpecWithRules(
 				} else {
 					schema = schemas[idx]
 				}
-				_, errList := gqlparser.LoadQueryWithRules(schema, spec.Query, nil)
+				_, errList := gqlparser.LoadQueryWithRules(
+					schema,
+					spec.Query,
+					optionalSpecRules(spec),
+				)
 				var finalErrors gqlerror.List
 				for _, err := range errList {
 					// ignore errors from other rules
@@ -297,6 +316,29 @@ func runSpecWithRules(
 	})
 }
 
+// optionalRules are left out of the default rules, so the specs of each are
+// run with it added.
+var optionalRules = map[string]core.Rule{
+	rules.DeferStreamDirectiveLabelRule.Name:             rules.DeferStreamDirectiveLabelRule,
+	rules.DeferStreamDirectiveOnRootFieldRule.Name:       rules.DeferStreamDirectiveOnRootFieldRule,
+	rules.DeferStreamDirectiveOnValidOperationsRule.Name: rules.DeferStreamDirectiveOnValidOperationsRule,
+	rules.NoDeprecatedCustomRule.Name:                    rules.NoDeprecatedCustomRule,
+	rules.NoSchemaIntrospectionCustomRule.Name:           rules.NoSchemaIntrospectionCustomRule,
+	rules.StreamDirectiveOnListFieldRule.Name:            rules.StreamDirectiveOnListFieldRule,
+}
+
+// optionalSpecRules is the default rules with the optional rule that spec
+// tests added, or nil when it tests a default rule.
+func optionalSpecRules(spec Spec) *rules.Rules {
+	rule, ok := optionalRules[spec.Rule]
+	if !ok {
+		return nil
+	}
+	specRules := rules.NewDefaultRules()
+	specRules.AddRule(rule.Name, rule.RuleFunc)
+	return specRules
+}
+
 func compareErrors(errors gqlerror.List) func(i, j int) bool {
 	return func(i, j int) bool {
 		cmp := strings.Compare(errors[i].Message, errors[j].Message)
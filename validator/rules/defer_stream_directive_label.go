@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// DeferStreamDirectiveLabelRule reports @defer and @stream labels that are not
+// static strings or are used more than once. Incremental delivery is not yet
+// part of the specification, so this is not one of the default rules.
+var DeferStreamDirectiveLabelRule = Rule{
+	Name: "DeferStreamDirectiveLabel",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		knownLabels := map[string]*ast.Directive{}
+		seen := map[*ast.Directive]bool{}
+		observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+			if _, ok := deferStreamNames[directive.Name]; !ok || seen[directive] {
+				return
+			}
+			seen[directive] = true
+
+			label := directive.Arguments.ForName("label")
+			if label == nil || label.Value == nil {
+				return
+			}
+			if label.Value.Kind != ast.StringValue && label.Value.Kind != ast.BlockValue {
+				addError(
+					Message(`Argument "@%s(label:)" must be a static string.`, directive.Name),
+					At(directive.Position),
+				)
+				return
+			}
+
+			if known, ok := knownLabels[label.Value.Raw]; ok {
+				addError(
+					Message(
+						`Value for arguments "defer(label:)" and "stream(label:)" must be unique `+
+							`across all Defer/Stream directive usages.`,
+					),
+					At(known.Position),
+					At(directive.Position),
+				)
+				return
+			}
+			knownLabels[label.Value.Raw] = directive
+		})
+	},
+}
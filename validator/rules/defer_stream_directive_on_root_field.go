@@ -0,0 +1,66 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// deferStreamNames maps the incremental delivery directives to the name used
+// for them in error messages.
+var deferStreamNames = map[string]string{
+	"defer":  "Defer",
+	"stream": "Stream",
+}
+
+// DeferStreamDirectiveOnRootFieldRule reports @defer and @stream on the root
+// fields of mutations and subscriptions. It is not one of the default rules.
+var DeferStreamDirectiveOnRootFieldRule = Rule{
+	Name: "DeferStreamDirectiveOnRootField",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		// Fragments are walked from each operation spreading them as well as
+		// on their own, so the same directive can be seen more than once.
+		reported := map[*ast.Directive]bool{}
+		check := func(walker *Walker, parentDef *ast.Definition, directives ast.DirectiveList) {
+			if parentDef == nil {
+				return
+			}
+			var rootType string
+			switch parentDef {
+			case walker.Schema.Mutation:
+				rootType = "mutation"
+			case walker.Schema.Subscription:
+				rootType = "subscription"
+			default:
+				return
+			}
+
+			for _, directive := range directives {
+				name, ok := deferStreamNames[directive.Name]
+				if !ok || reported[directive] {
+					continue
+				}
+				reported[directive] = true
+				addError(
+					Message(
+						`%s directive cannot be used on root %s type "%s".`,
+						name,
+						rootType,
+						parentDef.Name,
+					),
+					At(directive.Position),
+				)
+			}
+		}
+
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			check(walker, field.ObjectDefinition, field.Directives)
+		})
+		observers.OnInlineFragment(func(walker *Walker, inlineFragment *ast.InlineFragment) {
+			check(walker, inlineFragment.ObjectDefinition, inlineFragment.Directives)
+		})
+		observers.OnFragmentSpread(func(walker *Walker, fragmentSpread *ast.FragmentSpread) {
+			check(walker, fragmentSpread.ObjectDefinition, fragmentSpread.Directives)
+		})
+	},
+}
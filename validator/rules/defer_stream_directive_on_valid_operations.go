@@ -0,0 +1,55 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// DeferStreamDirectiveOnValidOperationsRule reports @defer and @stream in
+// subscriptions unless their "if" argument can be false. Like the other
+// incremental delivery rules, it has to be added to the default rules.
+var DeferStreamDirectiveOnValidOperationsRule = Rule{
+	Name: "DeferStreamDirectiveOnValidOperations",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		reported := map[*ast.Directive]bool{}
+		observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+			// Fragments are only checked when walked from a subscription
+			// spreading them.
+			operation := walker.CurrentOperation
+			if operation == nil || operation.Operation != ast.Subscription {
+				return
+			}
+			name, ok := deferStreamNames[directive.Name]
+			if !ok || reported[directive] || ifArgumentCanBeFalse(directive) {
+				return
+			}
+			reported[directive] = true
+			addError(
+				Message(
+					"%s directive not supported on subscription operations. "+
+						"Disable `@%s` by setting the `if` argument to `false`.",
+					name,
+					directive.Name,
+				),
+				At(directive.Position),
+			)
+		})
+	},
+}
+
+// ifArgumentCanBeFalse reports whether the if argument of directive is a
+// variable or the literal false.
+func ifArgumentCanBeFalse(directive *ast.Directive) bool {
+	arg := directive.Arguments.ForName("if")
+	if arg == nil || arg.Value == nil {
+		return false
+	}
+	switch arg.Value.Kind {
+	case ast.BooleanValue:
+		return arg.Value.Raw == "false"
+	case ast.Variable:
+		return true
+	}
+	return false
+}
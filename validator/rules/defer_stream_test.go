@@ -0,0 +1,126 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+const deferStreamSchema = `
+type Query { hero: Hero heroes: [Hero!]! }
+type Mutation { heroes: [Hero] }
+type Subscription { heroes: [Hero] }
+type Hero { name: String friends: [Hero] }
+`
+
+func TestDeferStreamRules(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: deferStreamSchema})
+	validate := func(rule core.Rule, query string) gqlerror.List {
+		_, errs := gqlparser.LoadQueryWithRules(schema, query, rules.NewRules(rule))
+		return errs
+	}
+	requireErrors := func(t *testing.T, errs gqlerror.List, messages ...string) {
+		t.Helper()
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Message)
+		}
+		require.Equal(t, messages, actual)
+	}
+
+	t.Run("on root field", func(t *testing.T) {
+		rule := rules.DeferStreamDirectiveOnRootFieldRule
+		require.Empty(t, validate(rule, `
+			{ heroes @stream { name } ... @defer { hero { name } } }
+			mutation { heroes { ... @defer { name } friends @stream { name } } }
+		`))
+		requireErrors(t, validate(rule, `
+			mutation { heroes @stream { name } ...F }
+			fragment F on Mutation { ... @defer { heroes { name } } }
+		`),
+			`Stream directive cannot be used on root mutation type "Mutation".`,
+			`Defer directive cannot be used on root mutation type "Mutation".`,
+		)
+		requireErrors(t, validate(rule, `subscription { ... @defer { heroes { name } } }`),
+			`Defer directive cannot be used on root subscription type "Subscription".`)
+	})
+
+	t.Run("on valid operations", func(t *testing.T) {
+		rule := rules.DeferStreamDirectiveOnValidOperationsRule
+		require.Empty(t, validate(rule, `
+			subscription ($stream: Boolean!) {
+				heroes { ... @defer(if: false) { name } friends @stream(if: $stream) { name } }
+			}
+			query { heroes { ...F } }
+			fragment F on Hero { friends @stream { name } }
+		`))
+		requireErrors(t, validate(rule, `
+			subscription { heroes { ...F ... @defer(if: true) { name } } }
+			query { heroes { ...F } }
+			fragment F on Hero { friends @stream { name } }
+		`),
+			"Stream directive not supported on subscription operations. "+
+				"Disable `@stream` by setting the `if` argument to `false`.",
+			"Defer directive not supported on subscription operations. "+
+				"Disable `@defer` by setting the `if` argument to `false`.",
+		)
+	})
+
+	t.Run("label", func(t *testing.T) {
+		rule := rules.DeferStreamDirectiveLabelRule
+		require.Empty(t, validate(rule, `
+			{ heroes @stream(label: "a") { ...F ...F } }
+			fragment F on Hero { ... @defer(label: "b") { name } }
+		`))
+		errs := validate(rule, `
+			query ($label: String) {
+				heroes @stream(label: "a") { ... @defer(label: "a") { name } }
+				hero { ... @defer(label: $label) { name } }
+			}
+		`)
+		requireErrors(t, errs,
+			`Value for arguments "defer(label:)" and "stream(label:)" must be unique `+
+				`across all Defer/Stream directive usages.`,
+			`Argument "@defer(label:)" must be a static string.`,
+		)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 13}, {Line: 3, Column: 39}},
+			errs[0].Locations)
+	})
+
+	t.Run("on list field", func(t *testing.T) {
+		rule := rules.StreamDirectiveOnListFieldRule
+		require.Empty(t, validate(rule, `{ heroes @stream { friends @stream { name } } }`))
+		requireErrors(t, validate(rule, `{ hero @stream { name @stream } }`),
+			`Directive "@stream" cannot be used on non-list field "Hero.name".`,
+			`Directive "@stream" cannot be used on non-list field "Query.hero".`,
+		)
+	})
+
+	t.Run("overlapping stream arguments", func(t *testing.T) {
+		rule := rules.OverlappingFieldsCanBeMergedRule
+		require.Empty(t, validate(rule, `{
+			heroes @stream(initialCount: 1) { name }
+			heroes @stream(initialCount: 1) { name }
+		}`))
+		requireErrors(t, validate(rule, `{
+			heroes @stream(initialCount: 1) { name }
+			heroes @stream(initialCount: 2) { name }
+		}`), `Fields "heroes" conflict because they have differing stream directives. `+
+			`Use different aliases on the fields to fetch both if this was intentional.`)
+		requireErrors(t, validate(rule, `{ heroes @stream { name } heroes { name } }`),
+			`Fields "heroes" conflict because they have differing stream directives. `+
+				`Use different aliases on the fields to fetch both if this was intentional.`)
+	})
+	t.Run("not in the default rules", func(t *testing.T) {
+		_, errs := gqlparser.LoadQueryWithRules(schema, `
+			subscription { heroes @stream(label: "a") { name @stream(label: "a") } }
+		`, rules.NewDefaultRules())
+		require.Empty(t, errs)
+	})
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// expandedCounter sums a count over the fields of a selection set with its
+// fragment spreads expanded, so that a fragment spread twice counts twice.
+// The count of each fragment is memoized so that fragments spreading each
+// other many times over are not expanded again, and sums stop growing just
+// past limit.
+type expandedCounter struct {
+	limit int
+	// count is what field adds, and whether its selection set is counted
+	// too.
+	count     func(field *ast.Field) (n int, descend bool)
+	fragments map[string]int
+	visiting  map[string]bool
+}
+
+func newExpandedCounter(
+	limit int,
+	count func(field *ast.Field) (n int, descend bool),
+) *expandedCounter {
+	return &expandedCounter{
+		limit:     limit,
+		count:     count,
+		fragments: map[string]int{},
+		visiting:  map[string]bool{},
+	}
+}
+
+func (c *expandedCounter) selectionSet(selectionSet ast.SelectionSet) int {
+	total := 0
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			n, descend := c.count(selection)
+			total = c.add(total, n)
+			if descend {
+				total = c.add(total, c.selectionSet(selection.SelectionSet))
+			}
+		case *ast.InlineFragment:
+			total = c.add(total, c.selectionSet(selection.SelectionSet))
+		case *ast.FragmentSpread:
+			total = c.add(total, c.fragment(selection))
+		}
+	}
+	return total
+}
+
+func (c *expandedCounter) fragment(fragmentSpread *ast.FragmentSpread) int {
+	fragment := fragmentSpread.Definition
+	if fragment == nil {
+		// Missing fragments checks are handled by `KnownFragmentNamesRule`.
+		return 0
+	}
+	if n, ok := c.fragments[fragment.Name]; ok {
+		return n
+	}
+	if c.visiting[fragment.Name] {
+		// Fragment cycles are handled by `NoFragmentCyclesRule`.
+		return 0
+	}
+
+	c.visiting[fragment.Name] = true
+	defer delete(c.visiting, fragment.Name)
+	n := c.selectionSet(fragment.SelectionSet)
+	c.fragments[fragment.Name] = n
+	return n
+}
+
+func (c *expandedCounter) add(a, b int) int {
+	return min(a+b, c.limit+1)
+}
+
+// operationName names operation in error messages.
+func operationName(operation *ast.OperationDefinition) string {
+	if operation.Name == "" {
+		return "Anonymous operation"
+	}
+	return "Operation " + strconv.Quote(operation.Name)
+}
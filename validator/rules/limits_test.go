@@ -0,0 +1,85 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+func TestLimitRules(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: depthSchema})
+	validate := func(rule core.Rule, query string) gqlerror.List {
+		_, errs := gqlparser.LoadQueryWithRules(schema, query, rules.NewRules(rule))
+		return errs
+	}
+	requireError := func(t *testing.T, errs gqlerror.List, message, code string, line, column int) {
+		t.Helper()
+		require.Len(t, errs, 1)
+		require.Equal(t, message, errs[0].Message)
+		require.Equal(t, code, errs[0].Extensions["code"])
+		require.Equal(t, []gqlerror.Location{{Line: line, Column: column}}, errs[0].Locations)
+	}
+
+	t.Run("aliases", func(t *testing.T) {
+		rule := rules.MaxAliasesRule(3)
+		require.Empty(t, validate(rule, `{ a: user { name } b: user { n: name } }`))
+		requireError(t, validate(rule, `
+			query Aliases { ...Names ...Names user { name } }
+			fragment Names on Query { a: user { n: name } }
+		`), `Operation "Aliases" uses more than 3 aliases.`, rules.MaxAliasesCode, 2, 4)
+	})
+
+	t.Run("field occurrences", func(t *testing.T) {
+		rule := rules.MaxFieldOccurrencesRule(2)
+		require.Empty(t, validate(rule, `{ a: user { name } b: user { name } }`))
+		requireError(t, validate(rule, `{
+			a: user { name }
+			...Users
+		}
+		fragment Users on Query { b: user { friends { __typename } } c: user { __typename } }`),
+			`Field "Query.user" is selected more than 2 times.`,
+			rules.MaxFieldOccurrencesCode, 2, 4)
+	})
+
+	t.Run("directives", func(t *testing.T) {
+		rule := rules.MaxDirectivesRule(2)
+		require.Empty(t, validate(rule, `{ user @skip(if: false) @include(if: true) { name } }`))
+		requireError(t, validate(rule, `
+			query A { ...F }
+			query B { ...F }
+			fragment F on Query { user { name @skip(if: false) @skip(if: false) @skip(if: false) } }
+		`), `More than 2 directives are used at one location.`, rules.MaxDirectivesCode, 4, 73)
+	})
+
+	t.Run("root fields", func(t *testing.T) {
+		rule := rules.MaxRootFieldsRule(2)
+		require.Empty(t, validate(rule, `{ a: user { name friends { name } } b: user { name } }`))
+		requireError(t, validate(rule, `{
+			a: user { name }
+			... on Query { b: user { name } c: user { name } }
+		}`), `Anonymous operation selects more than 2 root fields.`, rules.MaxRootFieldsCode, 1, 1)
+	})
+
+	t.Run("expanded fields", func(t *testing.T) {
+		rule := rules.MaxFieldsRule(100)
+		require.Empty(t, validate(rule, `
+			{ user { ...A } }
+			fragment A on User { name friends { name } }
+		`))
+		requireError(t, validate(rule, `
+			query Bomb { user { ...A } }
+			fragment A on User { ...B ...B ...B }
+			fragment B on User { ...C ...C ...C }
+			fragment C on User { ...D ...D ...D }
+			fragment D on User { ...E ...E ...E }
+			fragment E on User { name friends { name } }
+		`), `Operation "Bomb" selects more than 100 fields once fragments are expanded.`,
+			rules.MaxFieldsCode, 2, 4)
+	})
+}
@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxAliasesCode is the code extension of MaxAliasesRule errors.
+const MaxAliasesCode = "MAX_ALIASES_EXCEEDED"
+
+// MaxAliasesRule limits the number of aliases in an operation, counting
+// those in a fragment each time it is spread. Aliases let a single field be
+// resolved many times over in one request. It is not one of the default rules.
+func MaxAliasesRule(maxAliases int) Rule {
+	return Rule{
+		Name: "MaxAliases",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				c := newExpandedCounter(maxAliases, func(field *ast.Field) (int, bool) {
+					if field.Alias != "" && field.Alias != field.Name {
+						return 1, true
+					}
+					return 0, true
+				})
+				if c.selectionSet(operation.SelectionSet) <= maxAliases {
+					return
+				}
+
+				addError(
+					Message(
+						`%s uses more than %d aliases.`,
+						operationName(operation),
+						maxAliases,
+					),
+					At(operation.Position),
+					Code(MaxAliasesCode),
+				)
+			})
+		},
+	}
+}
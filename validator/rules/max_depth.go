@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxDepthOption configures MaxDepthRule.
+type MaxDepthOption func(*depthChecker)
+
+// MaxDepthIgnoreIntrospection leaves __schema and __type, and everything
+// selected under them, out of the depth. MaxIntrospectionDepth limits those.
+func MaxDepthIgnoreIntrospection() MaxDepthOption {
+	return func(c *depthChecker) {
+		c.ignoreIntrospection = true
+	}
+}
+
+// MaxDepthIgnoreTypename leaves __typename out of the depth, so that
+// selecting it on a leaf object does not add a level.
+func MaxDepthIgnoreTypename() MaxDepthOption {
+	return func(c *depthChecker) {
+		c.ignoreTypename = true
+	}
+}
+
+// MaxDepthRule limits how deeply the fields of an operation are nested,
+// following fragment spreads. A root field is at depth 1, so maxDepth 1
+// allows only leaf root fields. It is not one of the default rules.
+func MaxDepthRule(maxDepth int, options ...MaxDepthOption) Rule {
+	return Rule{
+		Name: "MaxDepth",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				c := &depthChecker{
+					fragmentDepths:   map[string]int{},
+					visitedFragments: map[string]bool{},
+				}
+				for _, option := range options {
+					option(c)
+				}
+
+				depth := c.selectionSetDepth(operation.SelectionSet)
+				if depth <= maxDepth {
+					return
+				}
+
+				position := operation.Position
+				if field := c.offendingField(operation.SelectionSet, 0, maxDepth); field != nil {
+					position = field.Position
+				}
+				addError(
+					Message(
+						`%s has a depth of %d, which exceeds the maximum depth of %d.`,
+						operationName(operation),
+						depth,
+						maxDepth,
+					),
+					At(position),
+				)
+			})
+		},
+	}
+}
+
+type depthChecker struct {
+	ignoreIntrospection bool
+	ignoreTypename      bool
+
+	// fragmentDepths memoizes the depth of each fragment, which does not
+	// depend on where it is spread.
+	fragmentDepths   map[string]int
+	visitedFragments map[string]bool
+}
+
+// counts reports whether field adds a level of depth.
+func (c *depthChecker) counts(field *ast.Field) bool {
+	switch field.Name {
+	case "__typename":
+		return !c.ignoreTypename
+	case "__schema", "__type":
+		return !c.ignoreIntrospection
+	}
+	return true
+}
+
+// selectionSetDepth is the depth of the deepest field in selectionSet.
+func (c *depthChecker) selectionSetDepth(selectionSet ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if c.counts(selection) {
+				depth = max(depth, 1+c.selectionSetDepth(selection.SelectionSet))
+			}
+		case *ast.InlineFragment:
+			depth = max(depth, c.selectionSetDepth(selection.SelectionSet))
+		case *ast.FragmentSpread:
+			depth = max(depth, c.fragmentDepth(selection))
+		}
+	}
+	return depth
+}
+
+func (c *depthChecker) fragmentDepth(fragmentSpread *ast.FragmentSpread) int {
+	fragment := fragmentSpread.Definition
+	if fragment == nil {
+		// Missing fragments checks are handled by `KnownFragmentNamesRule`.
+		return 0
+	}
+	if depth, ok := c.fragmentDepths[fragment.Name]; ok {
+		return depth
+	}
+	if c.visitedFragments[fragment.Name] {
+		// Fragment cycles are handled by `NoFragmentCyclesRule`.
+		return 0
+	}
+
+	c.visitedFragments[fragment.Name] = true
+	defer delete(c.visitedFragments, fragment.Name)
+	depth := c.selectionSetDepth(fragment.SelectionSet)
+	c.fragmentDepths[fragment.Name] = depth
+	return depth
+}
+
+// offendingField finds the first field of selectionSet, selected at depth,
+// that is deeper than maxDepth. Only branches known to go too deep are
+// followed.
+func (c *depthChecker) offendingField(
+	selectionSet ast.SelectionSet,
+	depth int,
+	maxDepth int,
+) *ast.Field {
+	for _, selection := range selectionSet {
+		var children ast.SelectionSet
+		var fragment *ast.FragmentDefinition
+		childDepth := depth
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !c.counts(selection) {
+				continue
+			}
+			if depth+1 > maxDepth {
+				return selection
+			}
+			children = selection.SelectionSet
+			childDepth = depth + 1
+		case *ast.InlineFragment:
+			children = selection.SelectionSet
+		case *ast.FragmentSpread:
+			fragment = selection.Definition
+			if fragment == nil || c.visitedFragments[fragment.Name] {
+				continue
+			}
+			c.visitedFragments[fragment.Name] = true
+			children = fragment.SelectionSet
+		}
+		var field *ast.Field
+		if childDepth+c.selectionSetDepth(children) > maxDepth {
+			field = c.offendingField(children, childDepth, maxDepth)
+		}
+		if fragment != nil {
+			delete(c.visitedFragments, fragment.Name)
+		}
+		if field != nil {
+			return field
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+const depthSchema = `
+type Query { user: User }
+type User { name: String friends: [User] }
+`
+
+func TestMaxDepthRule(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: depthSchema})
+	validate := func(query string, maxDepth int, options ...rules.MaxDepthOption) gqlerror.List {
+		rs := rules.NewRules(rules.MaxDepthRule(maxDepth, options...))
+		_, errs := gqlparser.LoadQueryWithRules(schema, query, rs)
+		return errs
+	}
+
+	t.Run("within the limit", func(t *testing.T) {
+		require.Empty(t, validate(`{ user { friends { name } } }`, 3))
+	})
+
+	t.Run("reports the operation and the first field too deep", func(t *testing.T) {
+		errs := validate(`query Deep {
+			user { name }
+			user { friends { friends { name } } }
+		}`, 3)
+		require.Len(t, errs, 1)
+		require.Equal(
+			t,
+			`Operation "Deep" has a depth of 4, which exceeds the maximum depth of 3.`,
+			errs[0].Message,
+		)
+		require.Equal(t, "MaxDepth", errs[0].Rule)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 31}}, errs[0].Locations)
+	})
+
+	t.Run("follows fragments", func(t *testing.T) {
+		errs := validate(`
+			{ user { ...Friends } }
+			fragment Friends on User { friends { ... on User { friends { name } } } }
+		`, 3)
+		require.Len(t, errs, 1)
+		require.Equal(
+			t,
+			`Anonymous operation has a depth of 4, which exceeds the maximum depth of 3.`,
+			errs[0].Message,
+		)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 65}}, errs[0].Locations)
+	})
+
+	t.Run("survives fragment cycles", func(t *testing.T) {
+		errs := validate(`
+			{ user { ...A } }
+			fragment A on User { ...B friends { name } }
+			fragment B on User { ...A friends { friends { name } } }
+		`, 2)
+		require.Len(t, errs, 1)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 40}}, errs[0].Locations)
+	})
+
+	t.Run("follows a fragment spread in sibling branches", func(t *testing.T) {
+		errs := validate(`
+			{ user { ...Name friends { ...Name } } }
+			fragment Name on User { name }
+		`, 2)
+		require.Len(t, errs, 1)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 28}}, errs[0].Locations)
+	})
+
+	t.Run("ignores introspection and __typename", func(t *testing.T) {
+		query := `{
+			user { friends { __typename } }
+			__schema { types { fields { name } } }
+		}`
+		require.Len(t, validate(query, 2), 1)
+		require.Len(t, validate(query, 2, rules.MaxDepthIgnoreIntrospection()), 1)
+		require.Empty(t, validate(
+			query,
+			2,
+			rules.MaxDepthIgnoreIntrospection(),
+			rules.MaxDepthIgnoreTypename(),
+		))
+	})
+}
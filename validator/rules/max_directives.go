@@ -0,0 +1,38 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxDirectivesCode is the code extension of MaxDirectivesRule errors.
+const MaxDirectivesCode = "MAX_DIRECTIVES_EXCEEDED"
+
+// MaxDirectivesRule limits the number of directives used at a single
+// location, such as on one field. Repeating a directive many times makes
+// every later rule and resolver do the work again. It is not one of the
+// default rules.
+func MaxDirectivesRule(maxDirectives int) Rule {
+	return Rule{
+		Name: "MaxDirectives",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			// Fragments are walked from each operation spreading them, so
+			// their directives are seen more than once.
+			seen := map[*ast.Directive]bool{}
+
+			observers.OnDirectiveList(func(walker *Walker, directives []*ast.Directive) {
+				if len(directives) <= maxDirectives || seen[directives[0]] {
+					return
+				}
+				seen[directives[0]] = true
+
+				addError(
+					Message(`More than %d directives are used at one location.`, maxDirectives),
+					At(directives[maxDirectives].Position),
+					Code(MaxDirectivesCode),
+				)
+			})
+		},
+	}
+}
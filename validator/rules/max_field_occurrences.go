@@ -0,0 +1,100 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxFieldOccurrencesCode is the code extension of MaxFieldOccurrencesRule
+// errors.
+const MaxFieldOccurrencesCode = "MAX_FIELD_OCCURRENCES_EXCEEDED"
+
+// MaxFieldOccurrencesRule limits how many times an operation selects the same
+// field of the same type, counting those in a fragment each time it is
+// spread. It is not one of the default rules.
+func MaxFieldOccurrencesRule(maxOccurrences int) Rule {
+	return Rule{
+		Name: "MaxFieldOccurrences",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				c := &occurrenceCounter{
+					limit:     maxOccurrences,
+					first:     map[string]*ast.Field{},
+					fragments: map[string]map[string]int{},
+					visiting:  map[string]bool{},
+				}
+				counts := map[string]int{}
+				c.selectionSet(operation.SelectionSet, counts)
+
+				for _, coordinate := range c.order {
+					if counts[coordinate] <= maxOccurrences {
+						continue
+					}
+					addError(
+						Message(
+							`Field "%s" is selected more than %d times.`,
+							coordinate,
+							maxOccurrences,
+						),
+						At(c.first[coordinate].Position),
+						Code(MaxFieldOccurrencesCode),
+					)
+				}
+			})
+		},
+	}
+}
+
+// occurrenceCounter is like expandedCounter, but counts each field
+// coordinate apart.
+type occurrenceCounter struct {
+	limit     int
+	first     map[string]*ast.Field
+	order     []string
+	fragments map[string]map[string]int
+	visiting  map[string]bool
+}
+
+func (c *occurrenceCounter) selectionSet(selectionSet ast.SelectionSet, counts map[string]int) {
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			coordinate := selection.Name
+			if selection.ObjectDefinition != nil {
+				coordinate = selection.ObjectDefinition.Name + "." + selection.Name
+			}
+			if c.first[coordinate] == nil {
+				c.first[coordinate] = selection
+				c.order = append(c.order, coordinate)
+			}
+			counts[coordinate] = min(counts[coordinate]+1, c.limit+1)
+			c.selectionSet(selection.SelectionSet, counts)
+		case *ast.InlineFragment:
+			c.selectionSet(selection.SelectionSet, counts)
+		case *ast.FragmentSpread:
+			for coordinate, n := range c.fragment(selection) {
+				counts[coordinate] = min(counts[coordinate]+n, c.limit+1)
+			}
+		}
+	}
+}
+
+func (c *occurrenceCounter) fragment(fragmentSpread *ast.FragmentSpread) map[string]int {
+	fragment := fragmentSpread.Definition
+	if fragment == nil || c.visiting[fragment.Name] {
+		// Missing fragments and fragment cycles are handled by
+		// `KnownFragmentNamesRule` and `NoFragmentCyclesRule`.
+		return nil
+	}
+	if counts, ok := c.fragments[fragment.Name]; ok {
+		return counts
+	}
+
+	c.visiting[fragment.Name] = true
+	defer delete(c.visiting, fragment.Name)
+	counts := map[string]int{}
+	c.selectionSet(fragment.SelectionSet, counts)
+	c.fragments[fragment.Name] = counts
+	return counts
+}
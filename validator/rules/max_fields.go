@@ -0,0 +1,39 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxFieldsCode is the code extension of MaxFieldsRule errors.
+const MaxFieldsCode = "MAX_FIELDS_EXCEEDED"
+
+// MaxFieldsRule limits the number of fields an operation selects once its
+// fragment spreads are expanded, which is what fragments spreading each other
+// over and over multiply. It is not one of the default rules.
+func MaxFieldsRule(maxFields int) Rule {
+	return Rule{
+		Name: "MaxFields",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				c := newExpandedCounter(maxFields, func(field *ast.Field) (int, bool) {
+					return 1, true
+				})
+				if c.selectionSet(operation.SelectionSet) <= maxFields {
+					return
+				}
+
+				addError(
+					Message(
+						`%s selects more than %d fields once fragments are expanded.`,
+						operationName(operation),
+						maxFields,
+					),
+					At(operation.Position),
+					Code(MaxFieldsCode),
+				)
+			})
+		},
+	}
+}
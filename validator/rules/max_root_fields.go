@@ -0,0 +1,39 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// MaxRootFieldsCode is the code extension of MaxRootFieldsRule errors.
+const MaxRootFieldsCode = "MAX_ROOT_FIELDS_EXCEEDED"
+
+// MaxRootFieldsRule limits the number of fields an operation selects on its
+// root type, counting those in a fragment each time it is spread. It is not
+// one of the default rules.
+func MaxRootFieldsRule(maxRootFields int) Rule {
+	return Rule{
+		Name: "MaxRootFields",
+		RuleFunc: func(observers *Events, addError AddErrFunc) {
+			observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+				c := newExpandedCounter(maxRootFields, func(field *ast.Field) (int, bool) {
+					return 1, false
+				})
+				if c.selectionSet(operation.SelectionSet) <= maxRootFields {
+					return
+				}
+
+				addError(
+					Message(
+						`%s selects more than %d root fields.`,
+						operationName(operation),
+						maxRootFields,
+					),
+					At(operation.Position),
+					Code(MaxRootFieldsCode),
+				)
+			})
+		},
+	}
+}
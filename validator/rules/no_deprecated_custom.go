@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// NoDeprecatedCustomRule reports the use of fields, arguments, input fields and
+// enum values marked @deprecated. It is not part of the validation section of
+// the specification, so it is not one of the default rules.
+var NoDeprecatedCustomRule = Rule{
+	Name: "NoDeprecatedCustom",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		// Fragments are walked from each operation spreading them as well as
+		// on their own, so the same node can be seen more than once.
+		reported := map[any]bool{}
+		report := func(node any, position *ast.Position, msg string, args ...any) {
+			if reported[node] {
+				return
+			}
+			reported[node] = true
+			addError(Message(msg, args...), At(position))
+		}
+
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			if field.Definition == nil || field.ObjectDefinition == nil {
+				return
+			}
+			if reason, ok := deprecationReason(field.Definition.Directives); ok {
+				report(
+					field,
+					field.Position,
+					`The field %s.%s is deprecated. %s`,
+					field.ObjectDefinition.Name,
+					field.Name,
+					reason,
+				)
+			}
+			for _, arg := range field.Arguments {
+				argDef := field.Definition.Arguments.ForName(arg.Name)
+				if argDef == nil {
+					continue
+				}
+				if reason, ok := deprecationReason(argDef.Directives); ok {
+					report(
+						arg,
+						arg.Position,
+						`Field "%s.%s" argument "%s" is deprecated. %s`,
+						field.ObjectDefinition.Name,
+						field.Name,
+						arg.Name,
+						reason,
+					)
+				}
+			}
+		})
+
+		observers.OnDirective(func(walker *Walker, directive *ast.Directive) {
+			if directive.Definition == nil {
+				return
+			}
+			for _, arg := range directive.Arguments {
+				argDef := directive.Definition.Arguments.ForName(arg.Name)
+				if argDef == nil {
+					continue
+				}
+				if reason, ok := deprecationReason(argDef.Directives); ok {
+					report(
+						arg,
+						arg.Position,
+						`Directive "@%s" argument "%s" is deprecated. %s`,
+						directive.Name,
+						arg.Name,
+						reason,
+					)
+				}
+			}
+		})
+
+		observers.OnValue(func(walker *Walker, value *ast.Value) {
+			if value.Definition == nil {
+				return
+			}
+			switch {
+			case value.Kind == ast.ObjectValue && value.Definition.Kind == ast.InputObject:
+				for _, child := range value.Children {
+					fieldDef := value.Definition.Fields.ForName(child.Name)
+					if fieldDef == nil {
+						continue
+					}
+					if reason, ok := deprecationReason(fieldDef.Directives); ok {
+						report(
+							child,
+							child.Position,
+							`The input field %s.%s is deprecated. %s`,
+							value.Definition.Name,
+							child.Name,
+							reason,
+						)
+					}
+				}
+			case value.Kind == ast.EnumValue && value.Definition.Kind == ast.Enum:
+				enumValue := value.Definition.EnumValues.ForName(value.Raw)
+				if enumValue == nil {
+					return
+				}
+				if reason, ok := deprecationReason(enumValue.Directives); ok {
+					report(
+						value,
+						value.Position,
+						`The enum value "%s.%s" is deprecated. %s`,
+						value.Definition.Name,
+						value.Raw,
+						reason,
+					)
+				}
+			}
+		})
+	},
+}
+
+// deprecationReason reports whether directives mark something as deprecated,
+// and why.
+func deprecationReason(directives ast.DirectiveList) (string, bool) {
+	deprecated := directives.ForName("deprecated")
+	if deprecated == nil {
+		return "", false
+	}
+	if arg := deprecated.Arguments.ForName("reason"); arg != nil && arg.Value != nil &&
+		(arg.Value.Kind == ast.StringValue || arg.Value.Kind == ast.BlockValue) {
+		return arg.Value.Raw, true
+	}
+	return "No longer supported", true
+}
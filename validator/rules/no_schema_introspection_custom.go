@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// introspectionTypes are the types of the introspection system.
+var introspectionTypes = map[string]bool{
+	"__Schema":            true,
+	"__Directive":         true,
+	"__DirectiveLocation": true,
+	"__Type":              true,
+	"__Field":             true,
+	"__InputValue":        true,
+	"__EnumValue":         true,
+	"__TypeKind":          true,
+}
+
+// NoSchemaIntrospectionCustomRule reports every field returning an
+// introspection type, such as __schema and __type but not __typename, to keep
+// clients from introspecting the schema. It is not part of the validation
+// section of the specification, so it is not one of the default rules.
+var NoSchemaIntrospectionCustomRule = Rule{
+	Name: "NoSchemaIntrospectionCustom",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		// Fragments are walked from each operation spreading them as well as
+		// on their own, so the same field can be seen more than once.
+		reported := map[*ast.Field]bool{}
+
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			if field.Definition == nil || reported[field] {
+				return
+			}
+			if !introspectionTypes[field.Definition.Type.Name()] {
+				return
+			}
+			reported[field] = true
+
+			addError(
+				Message(
+					`GraphQL introspection has been disabled, but the requested query contained the field "%s".`,
+					field.Name,
+				),
+				At(field.Position),
+			)
+		})
+	},
+}
@@ -520,6 +520,15 @@ func (m *overlappingFieldsCanBeMergedManager) findConflict(
 		}
 	}
 
+	// Two fields must be streamed the same way, or not at all.
+	if !sameStreams(fieldA.Directives, fieldB.Directives) {
+		return &ConflictMessage{
+			ResponseName: fieldNameA,
+			Message:      "they have differing stream directives",
+			Position:     fieldB.Position,
+		}
+	}
+
 	if fieldA.Definition != nil && fieldB.Definition != nil &&
 		doTypesConflict(m.walker, fieldA.Definition.Type, fieldB.Definition.Type) {
 		return &ConflictMessage{
@@ -570,6 +579,15 @@ func sameArguments(args1, args2 []*ast.Argument) bool {
 	return true
 }
 
+func sameStreams(directives1, directives2 ast.DirectiveList) bool {
+	stream1 := directives1.ForName("stream")
+	stream2 := directives2.ForName("stream")
+	if stream1 == nil || stream2 == nil {
+		return stream1 == stream2
+	}
+	return sameArguments(stream1.Arguments, stream2.Arguments)
+}
+
 func sameValue(value1, value2 *ast.Value) bool {
 	if value1.Kind != value2.Kind {
 		return false
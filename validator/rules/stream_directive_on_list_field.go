@@ -0,0 +1,36 @@
+package rules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// StreamDirectiveOnListFieldRule reports @stream on fields that are not lists.
+// It is opt-in, as are the @defer and @stream rules it goes with.
+var StreamDirectiveOnListFieldRule = Rule{
+	Name: "StreamDirectiveOnListField",
+	RuleFunc: func(observers *Events, addError AddErrFunc) {
+		reported := map[*ast.Directive]bool{}
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			if field.Definition == nil || field.ObjectDefinition == nil ||
+				field.Definition.Type.Elem != nil {
+				return
+			}
+			for _, directive := range field.Directives {
+				if directive.Name != "stream" || reported[directive] {
+					continue
+				}
+				reported[directive] = true
+				addError(
+					Message(
+						`Directive "@stream" cannot be used on non-list field "%s.%s".`,
+						field.ObjectDefinition.Name,
+						field.Name,
+					),
+					At(directive.Position),
+				)
+			}
+		})
+	},
+}
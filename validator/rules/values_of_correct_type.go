@@ -28,8 +28,28 @@ func ruleFuncValuesOfCorrectType(observers *Events, addError AddErrFunc, disable
 		}
 
 		if value.Definition.Kind == ast.Scalar {
-			// Skip custom validating scalars
+			// Custom scalars are only checked by the parsers registered for them.
 			if !value.Definition.OneOf("Int", "Float", "String", "Boolean", "ID") {
+				parser := walker.Schema.Scalars.ForDefinition(value.Definition)
+				if parser == nil || parser.ParseLiteral == nil ||
+					value.Kind == ast.Variable || value.Kind == ast.NullValue {
+					return
+				}
+				// The items of a list are checked on their own.
+				if value.Kind == ast.ListValue && value.ExpectedType.Elem != nil {
+					return
+				}
+				if err := parser.ParseLiteral(value); err != nil {
+					addError(
+						Message(
+							`Expected value of type "%s", found %s; %s`,
+							value.ExpectedType.String(),
+							value.String(),
+							err.Error(),
+						),
+						At(value.Position),
+					)
+				}
 				return
 			}
 		}
@@ -181,7 +201,7 @@ func ruleFuncValuesOfCorrectType(observers *Events, addError AddErrFunc, disable
 								Message(
 									`Field "%s.%s" must be non-null.`,
 									value.Definition.Name,
-									value.Definition.Fields[0].Name,
+									value.Children[0].Name,
 								),
 								At(fieldValue.Position),
 							)
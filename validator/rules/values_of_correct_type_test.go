@@ -0,0 +1,38 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+func TestValuesOfCorrectTypeOneOf(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { find(by: FindBy!): String }
+		input FindBy @oneOf { id: ID name: String }
+	`})
+	validate := func(query string) []string {
+		_, errs := gqlparser.LoadQueryWithRules(
+			schema,
+			query,
+			rules.NewRules(rules.ValuesOfCorrectTypeRule),
+		)
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Message)
+		}
+		return messages
+	}
+
+	require.Empty(t, validate(`{ find(by: {name: "a"}) }`))
+	require.Equal(t, []string{`OneOf Input Object "FindBy" must specify exactly one key.`},
+		validate(`{ find(by: {id: 1, name: "a"}) }`))
+	require.Equal(t, []string{`OneOf Input Object "FindBy" must specify exactly one key.`},
+		validate(`{ find(by: {}) }`))
+	require.Equal(t, []string{`Field "FindBy.name" must be non-null.`},
+		validate(`{ find(by: {name: null}) }`))
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/parser"
 	"github.com/vektah/gqlparser/v2/parser/testrunner"
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/sdlrules"
 )
 
 func TestLoadSchema(t *testing.T) {
@@ -31,6 +33,21 @@ func TestLoadSchema(t *testing.T) {
 		require.Equal(t, "defer", deferDef.Name, "@defer exists.")
 		require.Equal(t, "if", deferDef.Arguments[0].Name, "@defer has \"if\" argument.")
 		require.Equal(t, "label", deferDef.Arguments[1].Name, "@defer has \"label\" argument.")
+
+		streamDef := s.Directives["stream"]
+		require.Equal(t, "stream", streamDef.Name, "@stream exists.")
+		require.Equal(
+			t,
+			[]ast.DirectiveLocation{ast.LocationField},
+			streamDef.Locations,
+			"@stream is only allowed on fields.",
+		)
+		require.Equal(
+			t,
+			"initialCount",
+			streamDef.Arguments[2].Name,
+			"@stream has \"initialCount\" argument.",
+		)
 	})
 	t.Run("swapi", func(t *testing.T) {
 		file, err := os.ReadFile("testdata/swapi.graphql")
@@ -210,3 +227,128 @@ func TestUnionDuplicateMemberFallbackPosition(t *testing.T) {
 	require.Equal(t, 1, gerr.Locations[0].Line)
 	require.Equal(t, 7, gerr.Locations[0].Column)
 }
+
+func TestValidateSchemaDocumentAll(t *testing.T) {
+	sd, err := parser.ParseSchemas(Prelude, &ast.Source{Name: "t", Input: `type Query {
+	a: Missing
+	b: Int @undefined
+}
+type Query { c: Int }
+enum Color { RED RED }
+input In { self: In! }
+`})
+	require.NoError(t, err)
+
+	s, errs := ValidateSchemaDocumentAll(sd)
+	require.NotNil(t, s)
+	require.NotNil(t, s.Query)
+	require.NotNil(t, s.Types["Color"])
+
+	type located struct {
+		Message      string
+		Line, Column int
+	}
+	got := make([]located, 0, len(errs))
+	for _, e := range errs {
+		require.Len(t, e.Locations, 1)
+		got = append(got, located{e.Message, e.Locations[0].Line, e.Locations[0].Column})
+	}
+	require.Equal(t, []located{
+		{"Undefined type Missing.", 2, 5},
+		{"Undefined directive undefined.", 3, 10},
+		{"Cannot redeclare type Query.", 5, 6},
+		{"Enum value Color.RED can only be defined once.", 6, 18},
+		{
+			`Cannot reference Input Object "In" within itself through ` +
+				`a series of non-null fields: "self".`,
+			7, 12,
+		},
+	}, got)
+
+	_, err = ValidateSchemaDocument(sd)
+	require.EqualError(t, err, "t:5:6: Cannot redeclare type Query.")
+}
+
+// ValidateSchemaDocument reports the first error the walk finds.
+func TestValidateSchemaDocumentFirstError(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{
+			input: "type __Query { a: Int a: Int }",
+			err: `t:1:6: Name "__Query" must not begin with "__", which is reserved by ` +
+				`GraphQL introspection.`,
+		},
+		{
+			input: "union U = A | A | Missing\ntype A { a: Int }\ntype Query { u: U }",
+			err:   `t:1:7: Undefined type "Missing".`,
+		},
+		{
+			input: "input I { i: I! }\ntype Query { a(i: I): Int }\ntype Z { z: Missing }",
+			err: `t:1:11: Cannot reference Input Object "I" within itself through ` +
+				`a series of non-null fields: "i".`,
+		},
+		{
+			input: "type Query { a(x: Query): Int @nope }",
+			err: "t:1:16: cannot use Query as argument x because OBJECT is not a " +
+				"valid input type",
+		},
+		{
+			input: "input I { i: I! }\ntype Query { a(i: I): Int }\n" +
+				"directive @d(x: Missing) on FIELD",
+			err: `t:1:11: Cannot reference Input Object "I" within itself through ` +
+				`a series of non-null fields: "i".`,
+		},
+	} {
+		sd, err := parser.ParseSchemas(Prelude, &ast.Source{Name: "t", Input: tc.input})
+		require.NoError(t, err)
+		_, err = ValidateSchemaDocument(sd)
+		require.EqualError(t, err, tc.err, tc.input)
+	}
+}
+
+func TestValidateSchemaDocumentWithRules(t *testing.T) {
+	source := &ast.Source{Name: "t", Input: `type Query {
+	"The user"
+	user(id: ID): User @key
+}
+type User {
+	name: String
+}
+`}
+	sd, err := parser.ParseSchemas(Prelude, source)
+	require.NoError(t, err)
+
+	rules := sdlrules.NewDefaultRules()
+	rules.RemoveRule(sdlrules.KnownDirectivesRule.Name)
+	rules.AddRule("FieldDescriptions", func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnField(func(walker *SchemaWalker, field *ast.FieldDefinition) {
+			if walker.CurrentType.BuiltIn || field.Description != "" {
+				return
+			}
+			addError(
+				Message(
+					"Field %s.%s must have a description.",
+					walker.CurrentType.Name,
+					field.Name,
+				),
+				core.At(field.Position),
+			)
+		})
+	})
+
+	s, errs := ValidateSchemaDocumentWithRules(sd, rules)
+	require.NotNil(t, s)
+	require.Len(t, errs, 1)
+	require.Equal(t, "t:6:2: Field User.name must have a description.", errs[0].Error())
+	require.Equal(t, "FieldDescriptions", errs[0].Rule)
+
+	// Loading a schema document modifies it, so start from a fresh one.
+	sd, err = parser.ParseSchemas(Prelude, source)
+	require.NoError(t, err)
+	_, errs = ValidateSchemaDocumentAll(sd)
+	require.Len(t, errs, 1)
+	require.Equal(t, "t:3:22: Undefined directive key.", errs[0].Error())
+	require.Equal(t, "KnownDirectives", errs[0].Rule)
+}
@@ -0,0 +1,28 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+var ArgumentsAreInputTypesRule = SchemaRule{
+	Name: "ArgumentsAreInputTypes",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnArgument(func(walker *SchemaWalker, arg *ast.ArgumentDefinition) {
+			def := walker.Schema.Types[arg.Type.Name()]
+			if def == nil || def.IsInputType() {
+				return
+			}
+			addError(
+				Message(
+					"cannot use %s as argument %s because %s is not a valid input type",
+					arg.Type.String(),
+					arg.Name,
+					def.Kind,
+				),
+				At(arg.Position),
+			)
+		})
+	},
+}
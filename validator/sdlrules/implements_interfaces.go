@@ -0,0 +1,151 @@
+package sdlrules
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// ImplementsInterfacesRule checks that objects and interfaces correctly
+// implement the interfaces they declare. See the validation rules at the bottom
+// of https://spec.graphql.org/October2021/#sec-Objects
+var ImplementsInterfacesRule = SchemaRule{
+	Name: "ImplementsInterfaces",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			for _, intfName := range def.Interfaces {
+				validateImplements(walker.Schema, def, intfName, addError)
+			}
+		})
+	},
+}
+
+func validateImplements(
+	schema *ast.Schema,
+	def *ast.Definition,
+	intfName string,
+	addError AddErrFunc,
+) {
+	intf := schema.Types[intfName]
+	if intf == nil {
+		addError(Message("Undefined type %s.", strconv.Quote(intfName)), At(def.Position))
+		return
+	}
+	if intf.Kind != ast.Interface {
+		addError(
+			Message("%s is a non interface type %s.", strconv.Quote(intfName), intf.Kind),
+			At(def.Position),
+		)
+		return
+	}
+	for _, requiredField := range intf.Fields {
+		foundField := def.Fields.ForName(requiredField.Name)
+		if foundField == nil {
+			addError(
+				Message(
+					`For %s to implement %s it must have a field called %s.`,
+					def.Name, intf.Name, requiredField.Name,
+				),
+				At(def.Position),
+			)
+			continue
+		}
+
+		if !isCovariant(schema, requiredField.Type, foundField.Type) {
+			addError(
+				Message(
+					`For %s to implement %s the field %s must have type %s.`,
+					def.Name, intf.Name, requiredField.Name, requiredField.Type.String(),
+				),
+				At(foundField.Position),
+			)
+		}
+
+		for _, requiredArg := range requiredField.Arguments {
+			foundArg := foundField.Arguments.ForName(requiredArg.Name)
+			if foundArg == nil {
+				addError(
+					Message(
+						`For %s to implement %s the field %s must have the same arguments but it is missing %s.`,
+						def.Name, intf.Name, requiredField.Name, requiredArg.Name,
+					),
+					At(foundField.Position),
+				)
+				continue
+			}
+
+			if !requiredArg.Type.IsCompatible(foundArg.Type) {
+				addError(
+					Message(
+						`For %s to implement %s the field %s must have the same arguments but %s has the wrong type.`,
+						def.Name, intf.Name, requiredField.Name, requiredArg.Name,
+					),
+					At(foundArg.Position),
+				)
+			}
+		}
+		for _, foundArg := range foundField.Arguments {
+			if requiredField.Arguments.ForName(foundArg.Name) == nil && foundArg.Type.NonNull &&
+				foundArg.DefaultValue == nil {
+				addError(
+					Message(
+						`For %s to implement %s any additional arguments on %s must be optional or have a default value but %s is required.`,
+						def.Name, intf.Name, foundField.Name, foundArg.Name,
+					),
+					At(foundArg.Position),
+				)
+			}
+		}
+	}
+
+	// https://github.com/graphql/graphql-js/blob/47bd8c8897c72d3efc17ecb1599a95cee6bac5e8/src/type/validate.ts#L428
+	for _, transitive := range intf.Interfaces {
+		if slices.Contains(def.Interfaces, transitive) {
+			continue
+		}
+		if transitive == def.Name {
+			addError(
+				Message(
+					`Type %s cannot implement %s because it would create a circular reference.`,
+					def.Name, intfName,
+				),
+				At(def.Position),
+			)
+			continue
+		}
+		addError(
+			Message(
+				`Type %s must implement %s because it is implemented by %s.`,
+				def.Name, transitive, intfName,
+			),
+			At(def.Position),
+		)
+	}
+}
+
+func isCovariant(schema *ast.Schema, required, actual *ast.Type) bool {
+	if required.NonNull && !actual.NonNull {
+		return false
+	}
+
+	if required.NamedType != "" {
+		if required.NamedType == actual.NamedType {
+			return true
+		}
+		for _, pt := range schema.PossibleTypes[required.NamedType] {
+			if pt.Name == actual.NamedType {
+				return true
+			}
+		}
+		return false
+	}
+
+	if required.Elem != nil && actual.Elem == nil {
+		return false
+	}
+
+	return isCovariant(schema, required.Elem, actual.Elem)
+}
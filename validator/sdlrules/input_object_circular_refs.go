@@ -0,0 +1,71 @@
+package sdlrules
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// InputObjectCircularRefsRule rejects input object cycles of non-null fields.
+// https://spec.graphql.org/October2021/#sec-Input-Objects.Circular-References
+var InputObjectCircularRefsRule = SchemaRule{
+	Name: "InputObjectCircularRefs",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		// A type still on fieldPath means a cycle. A visited type was already checked.
+		visited := map[string]bool{}
+		var fieldPath []*ast.FieldDefinition
+		fieldPathIndexByTypeName := map[string]int{}
+
+		var detectCycle func(schema *ast.Schema, def *ast.Definition)
+		detectCycle = func(schema *ast.Schema, def *ast.Definition) {
+			if visited[def.Name] {
+				return
+			}
+			visited[def.Name] = true
+			fieldPathIndexByTypeName[def.Name] = len(fieldPath)
+
+			for _, field := range def.Fields {
+				// A nullable field or any list breaks the chain.
+				if !field.Type.NonNull || field.Type.NamedType == "" {
+					continue
+				}
+				fieldType := schema.Types[field.Type.NamedType]
+				if fieldType == nil || fieldType.Kind != ast.InputObject {
+					continue
+				}
+
+				fieldPath = append(fieldPath, field)
+				if cycleIndex, ok := fieldPathIndexByTypeName[fieldType.Name]; ok {
+					cyclePath := fieldPath[cycleIndex:]
+					fieldNames := make([]string, len(cyclePath))
+					for i, cycleField := range cyclePath {
+						fieldNames[i] = cycleField.Name
+					}
+					addError(
+						Message(
+							"Cannot reference Input Object %s within itself through "+
+								"a series of non-null fields: %s.",
+							strconv.Quote(fieldType.Name),
+							strconv.Quote(strings.Join(fieldNames, ".")),
+						),
+						At(cyclePath[0].Position),
+					)
+				} else {
+					detectCycle(schema, fieldType)
+				}
+				fieldPath = fieldPath[:len(fieldPath)-1]
+			}
+
+			delete(fieldPathIndexByTypeName, def.Name)
+		}
+
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			if def.Kind == ast.InputObject {
+				detectCycle(walker.Schema, def)
+			}
+		})
+	},
+}
@@ -0,0 +1,30 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+var KnownArgumentNamesOnDirectivesRule = SchemaRule{
+	Name: "KnownArgumentNamesOnDirectives",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnDirective(func(walker *SchemaWalker, directive *ast.Directive) {
+			if directive.Definition == nil {
+				return
+			}
+			for _, arg := range directive.Arguments {
+				if directive.Definition.Arguments.ForName(arg.Name) == nil {
+					addError(
+						Message(
+							"Undefined argument %s for directive %s.",
+							arg.Name,
+							directive.Name,
+						),
+						At(arg.Position),
+					)
+				}
+			}
+		})
+	},
+}
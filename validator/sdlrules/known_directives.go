@@ -0,0 +1,44 @@
+package sdlrules
+
+import (
+	"slices"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// KnownDirectivesRule checks that each directive used in the schema is defined,
+// is applicable where it is used, and is not used in its own definition.
+var KnownDirectivesRule = SchemaRule{
+	Name: "KnownDirectives",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnDirective(func(walker *SchemaWalker, directive *ast.Directive) {
+			current := walker.CurrentDirectiveDefinition
+			if current != nil && directive.Name == current.Name {
+				addError(
+					Message("Directive %s cannot refer to itself.", current.Name),
+					At(directive.Position),
+				)
+				return
+			}
+			if directive.Definition == nil {
+				addError(
+					Message("Undefined directive %s.", directive.Name),
+					At(directive.Position),
+				)
+				return
+			}
+			if !slices.Contains(directive.Definition.Locations, directive.Location) {
+				addError(
+					Message(
+						"Directive %s is not applicable on %s.",
+						directive.Name,
+						directive.Location,
+					),
+					At(directive.Position),
+				)
+			}
+		})
+	},
+}
@@ -0,0 +1,25 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+var KnownTypeNamesRule = SchemaRule{
+	Name: "KnownTypeNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		check := func(walker *SchemaWalker, typ *ast.Type) {
+			if walker.Schema.Types[typ.Name()] == nil {
+				addError(Message("Undefined type %s.", typ.Name()), At(typ.Position))
+			}
+		}
+
+		observers.OnField(func(walker *SchemaWalker, field *ast.FieldDefinition) {
+			check(walker, field.Type)
+		})
+		observers.OnArgument(func(walker *SchemaWalker, arg *ast.ArgumentDefinition) {
+			check(walker, arg.Type)
+		})
+	},
+}
@@ -0,0 +1,33 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// NonEmptyTypesRule requires objects, interfaces and input objects to define
+// at least one field, and enums at least one value.
+var NonEmptyTypesRule = SchemaRule{
+	Name: "NonEmptyTypes",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			var empty bool
+			var what string
+			switch def.Kind {
+			case ast.Object, ast.Interface:
+				empty, what = len(def.Fields) == 0, "fields"
+			case ast.Enum:
+				empty, what = len(def.EnumValues) == 0, "unique enum values"
+			case ast.InputObject:
+				empty, what = len(def.Fields) == 0, "input fields"
+			}
+			if empty {
+				addError(
+					Message("%s %s: must define one or more %s.", def.Kind, def.Name, what),
+					At(def.Position),
+				)
+			}
+		})
+	},
+}
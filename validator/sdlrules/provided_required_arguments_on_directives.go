@@ -0,0 +1,34 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+var ProvidedRequiredArgumentsOnDirectivesRule = SchemaRule{
+	Name: "ProvidedRequiredArgumentsOnDirectives",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnDirective(func(walker *SchemaWalker, directive *ast.Directive) {
+			if directive.Definition == nil {
+				return
+			}
+			for _, argDef := range directive.Definition.Arguments {
+				if !argDef.Type.NonNull || argDef.DefaultValue != nil {
+					continue
+				}
+				if arg := directive.Arguments.ForName(argDef.Name); arg == nil ||
+					arg.Value.Kind == ast.NullValue {
+					addError(
+						Message(
+							"Argument %s for directive %s cannot be null.",
+							argDef.Name,
+							directive.Name,
+						),
+						At(directive.Position),
+					)
+				}
+			}
+		})
+	},
+}
@@ -0,0 +1,48 @@
+package sdlrules
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// ReservedNamesRule rejects names beginning with "__", which are reserved for
+// introspection. Built-in types are exempt.
+var ReservedNamesRule = SchemaRule{
+	Name: "ReservedNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		check := func(position *ast.Position, name string) {
+			if strings.HasPrefix(name, "__") {
+				addError(
+					Message(
+						`Name "%s" must not begin with "__", which is reserved by GraphQL introspection.`,
+						name,
+					),
+					At(position),
+				)
+			}
+		}
+
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			if !def.BuiltIn {
+				check(def.Position, def.Name)
+			}
+		})
+		observers.OnField(func(walker *SchemaWalker, field *ast.FieldDefinition) {
+			check(field.Position, field.Name)
+		})
+		observers.OnArgument(func(walker *SchemaWalker, arg *ast.ArgumentDefinition) {
+			check(arg.Position, arg.Name)
+		})
+		observers.OnDirective(func(walker *SchemaWalker, directive *ast.Directive) {
+			check(directive.Position, directive.Name)
+		})
+		observers.OnDirectiveDefinition(
+			func(walker *SchemaWalker, def *ast.DirectiveDefinition) {
+				check(def.Position, def.Name)
+			},
+		)
+	},
+}
@@ -0,0 +1,116 @@
+package sdlrules
+
+import (
+	"slices"
+
+	"github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// Rules manages SDL validation rules, the ones applied to a schema as it is
+// loaded. It works like rules.Rules does for query validation.
+type Rules struct {
+	rules        map[string]core.SchemaRuleFunc
+	ruleNameKeys []string // for deterministic order
+}
+
+// NewRules creates a Rules instance with the specified rules.
+func NewRules(rs ...core.SchemaRule) *Rules {
+	r := &Rules{
+		rules: make(map[string]core.SchemaRuleFunc),
+	}
+
+	for _, rule := range rs {
+		r.AddRule(rule.Name, rule.RuleFunc)
+	}
+
+	return r
+}
+
+// NewDefaultRules creates a Rules instance containing the default SDL validation rule set.
+func NewDefaultRules() *Rules {
+	rules := []core.SchemaRule{
+		ArgumentsAreInputTypesRule,
+		ImplementsInterfacesRule,
+		InputObjectCircularRefsRule,
+		KnownArgumentNamesOnDirectivesRule,
+		KnownDirectivesRule,
+		KnownTypeNamesRule,
+		NonEmptyTypesRule,
+		ProvidedRequiredArgumentsOnDirectivesRule,
+		ReservedNamesRule,
+		UnionMembersRule,
+		UniqueArgumentDefinitionNamesRule,
+		UniqueDirectivesPerLocationRule,
+		UniqueEnumValueNamesRule,
+		UniqueFieldDefinitionNamesRule,
+		ValidEnumValueNamesRule,
+		ValidFieldTypesRule,
+	}
+
+	r := NewRules(rules...)
+
+	return r
+}
+
+// AddRule adds a rule with the specified name and rule function to the rule set.
+// If a rule with the same name already exists, it will not be added.
+func (r *Rules) AddRule(name string, ruleFunc core.SchemaRuleFunc) {
+	if r.rules == nil {
+		r.rules = make(map[string]core.SchemaRuleFunc)
+	}
+
+	if _, exists := r.rules[name]; !exists {
+		r.rules[name] = ruleFunc
+		r.ruleNameKeys = append(r.ruleNameKeys, name)
+	}
+}
+
+// GetInner returns the internal rule map.
+// If the map is not initialized, it returns an empty map.
+// This returns a copy of the rules map, not the original map.
+func (r *Rules) GetInner() map[string]core.SchemaRuleFunc {
+	if r == nil {
+		return nil
+	}
+	if r.rules == nil {
+		return make(map[string]core.SchemaRuleFunc)
+	}
+
+	rules := make(map[string]core.SchemaRuleFunc)
+	for k, v := range r.rules {
+		rules[k] = v
+	}
+
+	return rules
+}
+
+// RemoveRule removes a rule with the specified name from the rule set.
+// If no rule with the specified name exists, it does nothing.
+func (r *Rules) RemoveRule(name string) {
+	if r == nil {
+		return
+	}
+	if r.rules != nil {
+		delete(r.rules, name)
+	}
+
+	if len(r.ruleNameKeys) > 0 {
+		r.ruleNameKeys = slices.DeleteFunc(r.ruleNameKeys, func(s string) bool {
+			return s == name // delete the name rule key
+		})
+	}
+}
+
+// ReplaceRule replaces a rule with the specified name with a new rule function.
+// If no rule with the specified name exists, it does nothing.
+func (r *Rules) ReplaceRule(name string, ruleFunc core.SchemaRuleFunc) {
+	if r == nil {
+		return
+	}
+	if r.rules == nil {
+		r.rules = make(map[string]core.SchemaRuleFunc)
+	}
+	if _, exists := r.rules[name]; exists {
+		r.rules[name] = ruleFunc
+	}
+}
@@ -0,0 +1,64 @@
+package sdlrules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/validator/core"
+	"github.com/vektah/gqlparser/v2/validator/sdlrules"
+)
+
+func newDummyRule(name string) core.SchemaRule {
+	return core.SchemaRule{
+		Name:     name,
+		RuleFunc: func(*core.SchemaEvents, core.AddErrFunc) {},
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	rs := sdlrules.NewRules(newDummyRule("FirstRule"), newDummyRule("SecondRule"))
+	inner := rs.GetInner()
+
+	require.Len(t, inner, 2)
+	require.Equal(t, []string{"FirstRule", "SecondRule"}, rs.GetRuleNameKeys())
+}
+
+func TestAddRuleDuplicate(t *testing.T) {
+	rs := &sdlrules.Rules{}
+
+	rs.AddRule("DupRule", func(*core.SchemaEvents, core.AddErrFunc) {})
+	rs.AddRule("DupRule", func(*core.SchemaEvents, core.AddErrFunc) {})
+
+	require.Len(t, rs.GetInner(), 1)
+	require.Equal(t, []string{"DupRule"}, rs.GetRuleNameKeys())
+}
+
+func TestRemoveRule(t *testing.T) {
+	rs := sdlrules.NewDefaultRules()
+
+	rs.RemoveRule(sdlrules.KnownDirectivesRule.Name)
+
+	require.NotContains(t, rs.GetInner(), "KnownDirectives")
+	require.NotContains(t, rs.GetRuleNameKeys(), "KnownDirectives")
+}
+
+func TestReplaceRule(t *testing.T) {
+	rs := &sdlrules.Rules{}
+
+	rs.AddRule("Target", func(*core.SchemaEvents, core.AddErrFunc) {})
+	newFunc := func(*core.SchemaEvents, core.AddErrFunc) {}
+	rs.ReplaceRule("Target", newFunc)
+	rs.ReplaceRule("Missing", newFunc)
+
+	inner := rs.GetInner()
+	require.Len(t, inner, 1)
+	require.Equal(t, reflect.ValueOf(newFunc).Pointer(), reflect.ValueOf(inner["Target"]).Pointer())
+}
+
+func TestGetInnerNilSafety(t *testing.T) {
+	var rs *sdlrules.Rules
+	require.Nil(t, rs.GetInner())
+	require.Empty(t, (&sdlrules.Rules{}).GetInner())
+}
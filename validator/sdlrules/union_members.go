@@ -0,0 +1,63 @@
+package sdlrules
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// UnionMembersRule checks that union members are defined object types, each
+// included once.
+var UnionMembersRule = SchemaRule{
+	Name: "UnionMembers",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			seen := make(map[string]bool, len(def.Types))
+			for _, typ := range def.Types {
+				if seen[typ] {
+					continue
+				}
+				seen[typ] = true
+
+				typDef := walker.Schema.Types[typ]
+				if typDef == nil {
+					addError(Message("Undefined type %s.", strconv.Quote(typ)), At(def.Position))
+					continue
+				}
+				if typDef.Kind != ast.Object {
+					addError(
+						Message(
+							"%s type %s must be %s.",
+							def.Kind,
+							strconv.Quote(typ),
+							ast.Object,
+						),
+						At(def.Position),
+					)
+				}
+			}
+
+			// Duplicates point at the duplicate member's position when it is
+			// known. TypePositions is parallel to Types (populated by the
+			// parser); when it is absent or not aligned, fall back to the
+			// definition's own position.
+			memberPosAligned := len(def.TypePositions) == len(def.Types)
+			clear(seen)
+			for i, typ := range def.Types {
+				if seen[typ] {
+					pos := def.Position
+					if memberPosAligned && def.TypePositions[i] != nil {
+						pos = def.TypePositions[i]
+					}
+					addError(
+						Message("Union type %s can only include type %s once.", def.Name, typ),
+						At(pos),
+					)
+				}
+				seen[typ] = true
+			}
+		})
+	},
+}
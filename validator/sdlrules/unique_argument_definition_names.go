@@ -0,0 +1,41 @@
+package sdlrules
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// UniqueArgumentDefinitionNamesRule rejects an argument defined twice on a
+// field or directive.
+var UniqueArgumentDefinitionNamesRule = SchemaRule{
+	Name: "UniqueArgumentDefinitionNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		check := func(parent string, args ast.ArgumentDefinitionList) {
+			seen := make(map[string]bool, len(args))
+			for _, arg := range args {
+				if seen[arg.Name] {
+					addError(
+						Message(
+							"Argument %s can only be defined once.",
+							strconv.Quote(parent+"("+arg.Name+":)"),
+						),
+						At(arg.Position),
+					)
+				}
+				seen[arg.Name] = true
+			}
+		}
+
+		observers.OnField(func(walker *SchemaWalker, field *ast.FieldDefinition) {
+			check(walker.CurrentType.Name+"."+field.Name, field.Arguments)
+		})
+		observers.OnDirectiveDefinition(
+			func(walker *SchemaWalker, def *ast.DirectiveDefinition) {
+				check("@"+def.Name, def.Arguments)
+			},
+		)
+	},
+}
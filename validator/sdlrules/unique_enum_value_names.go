@@ -0,0 +1,27 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// UniqueEnumValueNamesRule rejects an enum value defined twice on an enum,
+// including across its extensions.
+var UniqueEnumValueNamesRule = SchemaRule{
+	Name: "UniqueEnumValueNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			seen := make(map[string]bool, len(def.EnumValues))
+			for _, value := range def.EnumValues {
+				if seen[value.Name] {
+					addError(
+						Message("Enum value %s.%s can only be defined once.", def.Name, value.Name),
+						At(value.Position),
+					)
+				}
+				seen[value.Name] = true
+			}
+		})
+	},
+}
@@ -0,0 +1,27 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// UniqueFieldDefinitionNamesRule rejects a field defined twice on a type,
+// including across its extensions.
+var UniqueFieldDefinitionNamesRule = SchemaRule{
+	Name: "UniqueFieldDefinitionNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			seen := make(map[string]bool, len(def.Fields))
+			for _, field := range def.Fields {
+				if seen[field.Name] {
+					addError(
+						Message("Field %s.%s can only be defined once.", def.Name, field.Name),
+						At(field.Position),
+					)
+				}
+				seen[field.Name] = true
+			}
+		})
+	},
+}
@@ -0,0 +1,25 @@
+package sdlrules
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// ValidEnumValueNamesRule rejects enum values that would read as another kind
+// of literal.
+var ValidEnumValueNamesRule = SchemaRule{
+	Name: "ValidEnumValueNames",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnEnumValue(func(walker *SchemaWalker, value *ast.EnumValueDefinition) {
+			switch value.Name {
+			case "true", "false", "null":
+				def := walker.CurrentType
+				addError(
+					Message("%s %s: non-enum value %s.", def.Kind, def.Name, value.Name),
+					At(def.Position),
+				)
+			}
+		})
+	},
+}
@@ -0,0 +1,64 @@
+package sdlrules
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	//nolint:staticcheck // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator/core"
+)
+
+// ValidFieldTypesRule checks that object and interface fields have output
+// types, and that input object fields have input types.
+var ValidFieldTypesRule = SchemaRule{
+	Name: "ValidFieldTypes",
+	RuleFunc: func(observers *SchemaEvents, addError AddErrFunc) {
+		observers.OnType(func(walker *SchemaWalker, def *ast.Definition) {
+			switch def.Kind {
+			case ast.Object, ast.Interface:
+				valid := []ast.DefinitionKind{
+					ast.Scalar, ast.Object, ast.Interface, ast.Union, ast.Enum,
+				}
+				for _, field := range def.Fields {
+					typ := walker.Schema.Types[field.Type.Name()]
+					if typ != nil && !slices.Contains(valid, typ.Kind) {
+						addError(
+							Message(
+								"%s %s: field must be one of %s.",
+								def.Kind,
+								def.Name,
+								kindList(valid...),
+							),
+							At(field.Position),
+						)
+					}
+				}
+			case ast.InputObject:
+				valid := []ast.DefinitionKind{ast.Scalar, ast.Enum, ast.InputObject}
+				for _, field := range def.Fields {
+					typ := walker.Schema.Types[field.Type.Name()]
+					if typ != nil && !slices.Contains(valid, typ.Kind) {
+						addError(
+							Message(
+								"%s %s: field must be one of %s.",
+								typ.Kind,
+								field.Name,
+								kindList(valid...),
+							),
+							At(field.Position),
+						)
+					}
+				}
+			}
+		})
+	},
+}
+
+func kindList(kinds ...ast.DefinitionKind) string {
+	s := make([]string, len(kinds))
+	for i, k := range kinds {
+		s[i] = string(k)
+	}
+	return strings.Join(s, ", ")
+}
@@ -17,6 +17,11 @@ type (
 	Events      = core.Events
 	ErrorOption = core.ErrorOption
 	Walker      = core.Walker
+
+	SchemaRuleFunc = core.SchemaRuleFunc
+	SchemaRule     = core.SchemaRule
+	SchemaEvents   = core.SchemaEvents
+	SchemaWalker   = core.SchemaWalker
 )
 
 var (
@@ -30,6 +35,11 @@ func Walk(schema *Schema, document *QueryDocument, observers *Events) {
 	core.Walk(schema, document, observers)
 }
 
+// WalkSchema is an alias for core.WalkSchema.
+func WalkSchema(schema *Schema, document *SchemaDocument, observers *SchemaEvents) {
+	core.WalkSchema(schema, document, observers)
+}
+
 var specifiedRules []Rule
 
 func init() {
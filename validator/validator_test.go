@@ -176,6 +176,24 @@ func TestDeprecatingTypes(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRedeclaringStream(t *testing.T) {
+	s := gqlparser.MustLoadSchema(
+		&ast.Source{Name: "graph/schema.graphqls", Input: `
+			directive @stream(initialCount: Int = 0, batch: Int) on FIELD
+
+			type Query {
+				a: [String]
+			}
+		`},
+	)
+	require.Len(t, s.Directives["stream"].Arguments, 2)
+
+	q, err := parser.ParseQuery(&ast.Source{Name: "ff", Input: `{ a @stream(batch: 2) }`})
+	require.NoError(t, err)
+
+	require.Nil(t, validator.Validate(s, q))
+}
+
 func TestNoUnusedVariables(t *testing.T) {
 	// https://github.com/99designs/gqlgen/issues/2028
 	t.Run("gqlgen issues #2028", func(t *testing.T) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -90,7 +91,7 @@ func VariableValues(
 					rv = rv.Elem()
 				}
 
-				rval, err := validator.validateVarType(v.Type, rv)
+				rval, _, err := validator.validateVarType(v.Type, rv)
 				if err != nil {
 					return nil, err
 				}
@@ -108,10 +109,12 @@ type varValidator struct {
 	schema *ast.Schema
 }
 
+// validateVarType checks val against typ, reporting whether the value it
+// returns was coerced from val rather than being val itself.
 func (v *varValidator) validateVarType(
 	typ *ast.Type,
 	val reflect.Value,
-) (reflect.Value, *gqlerror.Error) {
+) (reflect.Value, bool, *gqlerror.Error) {
 	currentPath := v.path
 	resetPath := func() {
 		v.path = currentPath
@@ -120,35 +123,48 @@ func (v *varValidator) validateVarType(
 
 	if !val.IsValid() {
 		if typ.NonNull {
-			return val, gqlerror.ErrorPathf(v.path, "cannot be null")
+			return val, false, gqlerror.ErrorPathf(v.path, "cannot be null")
 		}
-		return val, nil
+		return val, false, nil
 	}
 
 	if typ.Elem != nil {
+		wrapped := false
 		if val.Kind() != reflect.Slice {
 			// GraphQL spec says that non-null values should be coerced to an array when possible.
 			// Hence if the value is not a slice, we create a slice and add val to it.
 			slc := reflect.MakeSlice(reflect.SliceOf(val.Type()), 0, 0)
 			slc = reflect.Append(slc, val)
 			val = slc
+			wrapped = true
 		}
+		// Coerced items go in a copy of val, which belongs to the caller and
+		// may not be able to hold what they were coerced to.
+		items := make([]any, val.Len())
+		coerced := false
 		for i := 0; i < val.Len(); i++ {
 			resetPath()
 			v.path = append(v.path, ast.PathIndex(i))
 			field := val.Index(i)
 			if field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface {
 				if typ.Elem.NonNull && field.IsNil() {
-					return val, gqlerror.ErrorPathf(v.path, "cannot be null")
+					return val, false, gqlerror.ErrorPathf(v.path, "cannot be null")
 				}
 				field = field.Elem()
 			}
-			_, err := v.validateVarType(typ.Elem, field)
+			cval, itemCoerced, err := v.validateVarType(typ.Elem, field)
 			if err != nil {
-				return val, err
+				return val, false, err
 			}
+			coerced = coerced || itemCoerced
+			if cval.IsValid() {
+				items[i] = cval.Interface()
+			}
+		}
+		if coerced {
+			return copyList(val.Type(), items), true, nil
 		}
-		return val, nil
+		return val, wrapped, nil
 	}
 	def := v.schema.Types[typ.NamedType]
 	if def == nil {
@@ -160,7 +176,7 @@ func (v *varValidator) validateVarType(
 		kind := val.Type().Kind()
 		if kind != reflect.Int && kind != reflect.Int32 && kind != reflect.Int64 &&
 			kind != reflect.String {
-			return val, gqlerror.ErrorPathf(v.path, "enums must be ints or strings")
+			return val, false, gqlerror.ErrorPathf(v.path, "enums must be ints or strings")
 		}
 		isValidEnum := false
 		for _, enumVal := range def.EnumValues {
@@ -169,9 +185,14 @@ func (v *varValidator) validateVarType(
 			}
 		}
 		if !isValidEnum {
-			return val, gqlerror.ErrorPathf(v.path, "%s is not a valid %s", val.String(), def.Name)
+			return val, false, gqlerror.ErrorPathf(
+				v.path,
+				"%s is not a valid %s",
+				val.String(),
+				def.Name,
+			)
 		}
-		return val, nil
+		return val, false, nil
 	case ast.Scalar:
 		kind := val.Type().Kind()
 		switch typ.NamedType {
@@ -180,38 +201,57 @@ func (v *varValidator) validateVarType(
 				kind == reflect.Float32 ||
 				kind == reflect.Float64 ||
 				IsValidIntString(val, kind) {
-				return val, nil
+				return val, false, nil
 			}
 		case "Float":
 			if kind == reflect.Float32 || kind == reflect.Float64 || kind == reflect.Int ||
 				kind == reflect.Int32 ||
 				kind == reflect.Int64 ||
 				IsValidFloatString(val, kind) {
-				return val, nil
+				return val, false, nil
 			}
 		case "String":
 			if kind == reflect.String {
-				return val, nil
+				return val, false, nil
 			}
 
 		case "Boolean":
 			if kind == reflect.Bool {
-				return val, nil
+				return val, false, nil
 			}
 
 		case "ID":
 			if kind == reflect.Int || kind == reflect.Int32 || kind == reflect.Int64 ||
 				kind == reflect.String {
-				return val, nil
+				return val, false, nil
 			}
 		default:
-			// assume custom scalars are ok
-			return val, nil
+			// custom scalars are ok unless a parser is registered for them
+			parser := v.schema.Scalars.ForDefinition(def)
+			if parser == nil || parser.ParseValue == nil {
+				return val, false, nil
+			}
+			coerced, err := parser.ParseValue(val.Interface())
+			if err != nil {
+				return val, false, gqlerror.WrapPath(v.path, err)
+			}
+			// Going through a pointer keeps a nil result a valid value.
+			return reflect.ValueOf(&coerced).Elem(), true, nil
 		}
-		return val, gqlerror.ErrorPathf(v.path, "cannot use %s as %s", kind.String(), typ.NamedType)
+		return val, false, gqlerror.ErrorPathf(
+			v.path,
+			"cannot use %s as %s",
+			kind.String(),
+			typ.NamedType,
+		)
 	case ast.InputObject:
 		if val.Kind() != reflect.Map {
-			return val, gqlerror.ErrorPathf(v.path, "must be a %s, not a %s", def.Name, val.Kind())
+			return val, false, gqlerror.ErrorPathf(
+				v.path,
+				"must be a %s, not a %s",
+				def.Name,
+				val.Kind(),
+			)
 		}
 
 		// check for unknown fields
@@ -225,10 +265,19 @@ func (v *varValidator) validateVarType(
 			case name.String() == "__typename":
 				continue
 			case fieldDef == nil:
-				return val, gqlerror.ErrorPathf(v.path, "unknown field")
+				return val, false, gqlerror.ErrorPathf(v.path, "unknown field")
 			}
 		}
 
+		if def.Directives.ForName("oneOf") != nil {
+			resetPath()
+			if err := v.validateOneOf(def, val); err != nil {
+				return val, false, err
+			}
+		}
+
+		// As with lists, coerced fields go in a copy of val.
+		coerced := map[string]reflect.Value{}
 		for _, fieldDef := range def.Fields {
 			resetPath()
 			v.path = append(v.path, ast.PathName(fieldDef.Name))
@@ -243,14 +292,14 @@ func (v *varValidator) validateVarType(
 							continue
 						}
 					}
-					return val, gqlerror.ErrorPathf(v.path, "must be defined")
+					return val, false, gqlerror.ErrorPathf(v.path, "must be defined")
 				}
 				continue
 			}
 
 			if field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface {
 				if fieldDef.Type.NonNull && field.IsNil() {
-					return val, gqlerror.ErrorPathf(v.path, "cannot be null")
+					return val, false, gqlerror.ErrorPathf(v.path, "cannot be null")
 				}
 				// allow null object field and skip it
 				if !fieldDef.Type.NonNull && field.IsNil() {
@@ -258,16 +307,104 @@ func (v *varValidator) validateVarType(
 				}
 				field = field.Elem()
 			}
-			cval, err := v.validateVarType(fieldDef.Type, field)
+			cval, fieldCoerced, err := v.validateVarType(fieldDef.Type, field)
 			if err != nil {
-				return val, err
+				return val, false, err
+			}
+			if fieldCoerced {
+				coerced[fieldDef.Name] = cval
 			}
-			val.SetMapIndex(reflect.ValueOf(fieldDef.Name), cval)
+		}
+		if len(coerced) > 0 {
+			return copyInputObject(val, coerced), true, nil
 		}
 	default:
 		panic(fmt.Errorf("unsupported type %s", def.Kind))
 	}
-	return val, nil
+	return val, false, nil
+}
+
+// copyList returns items as a slice of type typ, or as a []any when they do
+// not all fit in one.
+func copyList(typ reflect.Type, items []any) reflect.Value {
+	list := reflect.MakeSlice(typ, len(items), len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		value := reflect.ValueOf(item)
+		if !value.Type().AssignableTo(typ.Elem()) {
+			return reflect.ValueOf(items)
+		}
+		list.Index(i).Set(value)
+	}
+	return list
+}
+
+// copyInputObject returns a copy of the map obj with the fields in coerced
+// replaced, which is a map[string]any when they do not fit in the type of obj.
+func copyInputObject(obj reflect.Value, coerced map[string]reflect.Value) reflect.Value {
+	typ := obj.Type()
+	fits := true
+	for _, value := range coerced {
+		if value.IsValid() && !value.Type().AssignableTo(typ.Elem()) {
+			fits = false
+		}
+	}
+
+	if !fits {
+		fields := make(map[string]any, obj.Len())
+		for _, name := range obj.MapKeys() {
+			fields[name.String()] = obj.MapIndex(name).Interface()
+		}
+		for name, value := range coerced {
+			fields[name] = nil
+			if value.IsValid() {
+				fields[name] = value.Interface()
+			}
+		}
+		return reflect.ValueOf(fields)
+	}
+
+	result := reflect.MakeMapWithSize(typ, obj.Len())
+	for _, name := range obj.MapKeys() {
+		result.SetMapIndex(name, obj.MapIndex(name))
+	}
+	for name, value := range coerced {
+		if !value.IsValid() {
+			value = reflect.Zero(typ.Elem())
+		}
+		result.SetMapIndex(reflect.ValueOf(name).Convert(typ.Key()), value)
+	}
+	return result
+}
+
+// validateOneOf checks that exactly one field of the OneOf Input Object def is
+// given in val, and that it is not null.
+func (v *varValidator) validateOneOf(def *ast.Definition, val reflect.Value) *gqlerror.Error {
+	var keys []reflect.Value
+	for _, name := range val.MapKeys() {
+		if name.String() != "__typename" {
+			keys = append(keys, name)
+		}
+	}
+	if len(keys) != 1 {
+		return gqlerror.ErrorPathf(
+			v.path,
+			`Exactly one key must be specified for OneOf type "%s".`,
+			def.Name,
+		)
+	}
+
+	field := val.MapIndex(keys[0])
+	if field.Kind() == reflect.Interface || field.Kind() == reflect.Pointer {
+		field = field.Elem()
+	}
+	if !field.IsValid() || (field.Kind() == reflect.Pointer && field.IsNil()) {
+		path := append(slices.Clone(v.path), ast.PathName(keys[0].String()))
+		return gqlerror.ErrorPathf(path, `Field "%s" must be non-null.`, keys[0].String())
+	}
+	return nil
 }
 
 func IsValidIntString(val reflect.Value, kind reflect.Kind) bool {
@@ -1,8 +1,11 @@
 package validator_test
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -261,6 +264,47 @@ func TestValidateVars(t *testing.T) {
 		})
 	})
 
+	t.Run("oneOf input object", func(t *testing.T) {
+		//nolint:staticcheck
+		q := gqlparser.MustLoadQuery(schema, `query($var: OneOfInput!) { oneOfArg(i: $var) }`)
+		validate := func(value map[string]any) error {
+			_, gerr := validator.VariableValues(
+				schema,
+				q.Operations.ForName(""),
+				map[string]any{"var": value},
+			)
+			return gerr
+		}
+
+		t.Run("exactly one field", func(t *testing.T) {
+			require.NoError(t, validate(map[string]any{"name": "foo"}))
+			require.NoError(t, validate(map[string]any{"__typename": "OneOfInput", "id": 1}))
+			require.NoError(t, validate(map[string]any{
+				"embedded": map[string]any{"name": "foo"},
+			}))
+		})
+
+		t.Run("no fields", func(t *testing.T) {
+			require.EqualError(t, validate(map[string]any{}),
+				`input: variable.var Exactly one key must be specified for OneOf type "OneOfInput".`)
+		})
+
+		t.Run("more than one field", func(t *testing.T) {
+			require.EqualError(t, validate(map[string]any{"name": "foo", "id": nil}),
+				`input: variable.var Exactly one key must be specified for OneOf type "OneOfInput".`)
+		})
+
+		t.Run("null field", func(t *testing.T) {
+			require.EqualError(t, validate(map[string]any{"id": nil}),
+				`input: variable.var.id Field "id" must be non-null.`)
+		})
+
+		t.Run("invalid field value", func(t *testing.T) {
+			require.EqualError(t, validate(map[string]any{"embedded": map[string]any{}}),
+				"input: variable.var.embedded.name must be defined")
+		})
+	})
+
 	t.Run("array", func(t *testing.T) {
 		t.Run("non-null object value should be coerced to an array", func(t *testing.T) {
 			//nolint:staticcheck
@@ -1215,3 +1259,107 @@ func mustReadFile(name string) string {
 
 	return string(src)
 }
+
+func TestValidateVarsWithScalarParsers(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query {
+			at(t: DateTime, ts: [DateTime!]): Boolean
+			id(id: UUID): Boolean
+			between(r: Range): Boolean
+		}
+		input Range { from: DateTime, to: DateTime }
+		scalar DateTime
+		scalar UUID @specifiedBy(url: "https://tools.ietf.org/html/rfc4122")
+	`})
+	parseTime := func(value any) (any, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent %v", value)
+		}
+		return time.Parse(time.RFC3339, s)
+	}
+	schema.RegisterScalar("DateTime", func(value *ast.Value) error {
+		_, err := parseTime(value.Raw)
+		if value.Kind != ast.StringValue || err != nil {
+			return fmt.Errorf("DateTime cannot represent %s", value.String())
+		}
+		return nil
+	}, parseTime)
+	schema.RegisterScalar("https://tools.ietf.org/html/rfc4122", func(value *ast.Value) error {
+		if len(value.Raw) != 36 {
+			return errors.New("not a UUID")
+		}
+		return nil
+	}, nil)
+
+	t.Run("literals", func(t *testing.T) {
+		_, errs := gqlparser.LoadQueryWithRules(schema, `{
+			at(t: "2024-01-02T03:04:05Z", ts: ["2024-01-02T03:04:05Z", "yesterday"])
+			id(id: "123")
+		}`, nil)
+		require.Len(t, errs, 2)
+		require.Equal(
+			t,
+			`Expected value of type "DateTime!", found "yesterday"; `+
+				`DateTime cannot represent "yesterday"`,
+			errs[0].Message,
+		)
+		require.Equal(
+			t,
+			`Expected value of type "UUID", found "123"; not a UUID`,
+			errs[1].Message,
+		)
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		q := gqlparser.MustLoadQueryWithRules(
+			schema,
+			`query($t: DateTime, $ts: [DateTime!], $id: UUID) { at(t: $t, ts: $ts) id(id: $id) }`,
+			nil,
+		)
+		vars, err := validator.VariableValues(schema, q.Operations[0], map[string]any{
+			"t":  "2024-01-02T03:04:05Z",
+			"ts": []any{"2024-01-02T03:04:05Z"},
+			"id": "any value",
+		})
+		require.NoError(t, err)
+		expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		require.Equal(t, expected, vars["t"])
+		require.Equal(t, []any{expected}, vars["ts"])
+		require.Equal(t, "any value", vars["id"])
+
+		_, err = validator.VariableValues(schema, q.Operations[0], map[string]any{
+			"ts": []any{"2024-01-02T03:04:05Z", 1},
+		})
+		require.EqualError(t, err, "input: variable.ts[1] DateTime cannot represent 1")
+	})
+
+	t.Run("variables are copied when coerced", func(t *testing.T) {
+		q := gqlparser.MustLoadQueryWithRules(
+			schema,
+			`query($ts: [DateTime!], $r: Range) { at(ts: $ts) between(r: $r) }`,
+			nil,
+		)
+		expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		ts := []any{"2024-01-02T03:04:05Z"}
+		r := map[string]any{"from": "2024-01-02T03:04:05Z", "to": nil}
+		vars, err := validator.VariableValues(schema, q.Operations[0], map[string]any{
+			"ts": ts,
+			"r":  r,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []any{expected}, vars["ts"])
+		require.Equal(t, map[string]any{"from": expected, "to": nil}, vars["r"])
+		require.Equal(t, []any{"2024-01-02T03:04:05Z"}, ts)
+		require.Equal(t, map[string]any{"from": "2024-01-02T03:04:05Z", "to": nil}, r)
+
+		vars, err = validator.VariableValues(schema, q.Operations[0], map[string]any{
+			"ts": []string{"2024-01-02T03:04:05Z"},
+			"r":  map[string]string{"from": "2024-01-02T03:04:05Z"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []any{expected}, vars["ts"])
+		require.Equal(t, map[string]any{"from": expected}, vars["r"])
+	})
+}